@@ -10,7 +10,11 @@ type VisitorExpr interface {
 	VisitAssignExpr(expr *Assign) (interface{}, error)
 	VisitBinaryExpr(expr *Binary) (interface{}, error)
 	VisitCallExpr(expr *Call) (interface{}, error)
+	VisitCoalesceExpr(expr *Coalesce) (interface{}, error)
+	VisitConditionalExpr(expr *Conditional) (interface{}, error)
 	VisitGroupingExpr(expr *Grouping) (interface{}, error)
+	VisitIncrementExpr(expr *Increment) (interface{}, error)
+	VisitInterpolationExpr(expr *Interpolation) (interface{}, error)
 	VisitLiteralExpr(expr *Literal) (interface{}, error)
 	VisitLogicalExpr(expr *Logical) (interface{}, error)
 	VisitUnaryExpr(expr *Unary) (interface{}, error)
@@ -50,10 +54,11 @@ type Call struct {
 	Callee Expr
 	Paren token.Token
 	Arguments []Expr
+	NamedArguments []NamedArgument
 }
 
-func NewCall(Callee Expr, Paren token.Token, Arguments []Expr) *Call {
-	 return &Call{Callee: Callee, Paren: Paren, Arguments: Arguments}
+func NewCall(Callee Expr, Paren token.Token, Arguments []Expr, NamedArguments []NamedArgument) *Call {
+	 return &Call{Callee: Callee, Paren: Paren, Arguments: Arguments, NamedArguments: NamedArguments}
 }
 
 func (e *Call) Accept(v VisitorExpr) (interface{}, error) {
@@ -61,6 +66,36 @@ func (e *Call) Accept(v VisitorExpr) (interface{}, error) {
 }
 
 
+type Coalesce struct {
+	Left Expr
+	Operator token.Token
+	Right Expr
+}
+
+func NewCoalesce(Left Expr, Operator token.Token, Right Expr) *Coalesce {
+	 return &Coalesce{Left: Left, Operator: Operator, Right: Right}
+}
+
+func (e *Coalesce) Accept(v VisitorExpr) (interface{}, error) {
+	return v.VisitCoalesceExpr(e)
+}
+
+
+type Conditional struct {
+	Condition Expr
+	ThenBranch Expr
+	ElseBranch Expr
+}
+
+func NewConditional(Condition Expr, ThenBranch Expr, ElseBranch Expr) *Conditional {
+	 return &Conditional{Condition: Condition, ThenBranch: ThenBranch, ElseBranch: ElseBranch}
+}
+
+func (e *Conditional) Accept(v VisitorExpr) (interface{}, error) {
+	return v.VisitConditionalExpr(e)
+}
+
+
 type Grouping struct {
 	Expression Expr
 }
@@ -74,6 +109,34 @@ func (e *Grouping) Accept(v VisitorExpr) (interface{}, error) {
 }
 
 
+type Increment struct {
+	Name token.Token
+	Operator token.Token
+	Prefix bool
+}
+
+func NewIncrement(Name token.Token, Operator token.Token, Prefix bool) *Increment {
+	 return &Increment{Name: Name, Operator: Operator, Prefix: Prefix}
+}
+
+func (e *Increment) Accept(v VisitorExpr) (interface{}, error) {
+	return v.VisitIncrementExpr(e)
+}
+
+
+type Interpolation struct {
+	Parts []Expr
+}
+
+func NewInterpolation(Parts []Expr) *Interpolation {
+	 return &Interpolation{Parts: Parts}
+}
+
+func (e *Interpolation) Accept(v VisitorExpr) (interface{}, error) {
+	return v.VisitInterpolationExpr(e)
+}
+
+
 type Literal struct {
 	Value interface{}
 }
@@ -0,0 +1,308 @@
+package ast
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"glox/token"
+	"reflect"
+	"unicode"
+	"unicode/utf8"
+)
+
+// nodeKinds lists every node type that can appear in a JSON syntax tree.
+var nodeKinds = map[string]reflect.Type{}
+
+func init() {
+	for _, node := range []interface{}{
+		Assign{}, Binary{}, Call{}, Coalesce{}, Conditional{}, Grouping{}, Increment{}, Interpolation{}, Literal{}, Logical{}, Unary{}, Variable{},
+		Block{}, Expression{}, ForIn{}, Function{}, If{}, Print{}, Return{}, Select{}, Spawn{}, Var{}, While{}, Yield{},
+		NamedArgument{}, Param{}, SelectCase{}, Type{},
+	} {
+		t := reflect.TypeOf(node)
+		nodeKinds[t.Name()] = t
+	}
+}
+
+var (
+	exprType  = reflect.TypeOf((*Expr)(nil)).Elem()
+	stmtType  = reflect.TypeOf((*Stmt)(nil)).Elem()
+	tokenType = reflect.TypeOf(token.Token{})
+)
+
+// jsonToken mirrors the JSON form of a token.Token for decoding.
+type jsonToken struct {
+	Type    token.TokenType `json:"type"`
+	Lexeme  string          `json:"lexeme"`
+	Literal interface{}     `json:"literal,omitempty"`
+	Line    int             `json:"line"`
+	Column  int             `json:"column"`
+}
+
+// jsonField is a single key of a JSON node, kept in declaration order.
+type jsonField struct {
+	key   string
+	value interface{}
+}
+
+type jsonNode []jsonField
+
+func (n jsonNode) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+
+	b.WriteString("{")
+
+	for i, f := range n {
+		if i > 0 {
+			b.WriteString(",")
+		}
+
+		key, _ := json.Marshal(f.key)
+		value, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+
+		b.Write(key)
+		b.WriteString(":")
+		b.Write(value)
+	}
+
+	b.WriteString("}")
+
+	return b.Bytes(), nil
+}
+
+// MarshalJSON encodes statements as a JSON tree. Every node is an object
+// whose "kind" key names its type, followed by its fields.
+func MarshalJSON(statements []Stmt, indent string) ([]byte, error) {
+	tree, err := encodeValue(reflect.ValueOf(statements))
+	if err != nil {
+		return nil, err
+	}
+
+	if indent == "" {
+		return json.Marshal(tree)
+	}
+
+	return json.MarshalIndent(tree, "", indent)
+}
+
+func encodeValue(v reflect.Value) (interface{}, error) {
+	switch {
+	case v.Type() == tokenType:
+		return v.Interface(), nil
+
+	case v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer:
+		if v.IsNil() {
+			return nil, nil
+		}
+
+		if v.Kind() == reflect.Interface || v.Elem().Type() == tokenType {
+			return encodeValue(v.Elem())
+		}
+
+		return encodeNode(v.Elem())
+
+	case v.Kind() == reflect.Slice:
+		elements := make([]interface{}, v.Len())
+
+		for i := range elements {
+			e, err := encodeValue(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+
+			elements[i] = e
+		}
+
+		return elements, nil
+
+	case v.Kind() == reflect.Struct:
+		return encodeNode(v)
+	}
+
+	return v.Interface(), nil
+}
+
+func encodeNode(v reflect.Value) (interface{}, error) {
+	t := v.Type()
+
+	if _, ok := nodeKinds[t.Name()]; !ok {
+		return nil, fmt.Errorf("cannot encode value of type %v", t)
+	}
+
+	node := jsonNode{{"kind", t.Name()}}
+
+	for i := 0; i < t.NumField(); i++ {
+		value, err := encodeValue(v.Field(i))
+		if err != nil {
+			return nil, err
+		}
+
+		node = append(node, jsonField{fieldKey(t.Field(i).Name), value})
+	}
+
+	return node, nil
+}
+
+// UnmarshalJSON decodes a tree produced by MarshalJSON back into statements.
+func UnmarshalJSON(data []byte) ([]Stmt, error) {
+	var raw interface{}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	statements := []Stmt{}
+	v := reflect.ValueOf(&statements).Elem()
+
+	if err := decodeValue(raw, v, "$"); err != nil {
+		return nil, err
+	}
+
+	return statements, nil
+}
+
+func decodeValue(raw interface{}, v reflect.Value, path string) error {
+	t := v.Type()
+
+	switch {
+	case t == tokenType:
+		return decodeToken(raw, v, path)
+
+	case t == exprType || t == stmtType:
+		if raw == nil {
+			return nil
+		}
+
+		node, err := decodeNode(raw, path)
+		if err != nil {
+			return err
+		}
+
+		if !node.Type().Implements(t) {
+			return fmt.Errorf("%v: %v is not a valid %v", path, node.Elem().Type().Name(), t.Name())
+		}
+
+		v.Set(node)
+		return nil
+
+	case t.Kind() == reflect.Slice:
+		elements, ok := raw.([]interface{})
+		if !ok {
+			return fmt.Errorf("%v: expected an array", path)
+		}
+
+		slice := reflect.MakeSlice(t, len(elements), len(elements))
+
+		for i, e := range elements {
+			if err := decodeValue(e, slice.Index(i), fmt.Sprintf("%v[%v]", path, i)); err != nil {
+				return err
+			}
+		}
+
+		v.Set(slice)
+		return nil
+
+	case t.Kind() == reflect.Pointer:
+		if raw == nil {
+			return nil
+		}
+
+		if t.Elem() == tokenType {
+			v.Set(reflect.New(tokenType))
+			return decodeToken(raw, v.Elem(), path)
+		}
+
+		node, err := decodeNode(raw, path)
+		if err != nil {
+			return err
+		}
+
+		if node.Type() != t {
+			return fmt.Errorf("%v: %v is not a valid %v", path, node.Elem().Type().Name(), t.Elem().Name())
+		}
+
+		v.Set(node)
+		return nil
+
+	case t.Kind() == reflect.Struct:
+		node, err := decodeNode(raw, path)
+		if err != nil {
+			return err
+		}
+
+		if node.Type().Elem() != t {
+			return fmt.Errorf("%v: %v is not a valid %v", path, node.Elem().Type().Name(), t.Name())
+		}
+
+		v.Set(node.Elem())
+		return nil
+
+	case t.Kind() == reflect.Interface:
+		if raw != nil {
+			v.Set(reflect.ValueOf(raw))
+		}
+
+		return nil
+	}
+
+	if raw == nil {
+		return nil
+	}
+
+	value := reflect.ValueOf(raw)
+	if !value.Type().ConvertibleTo(t) || value.Kind() != t.Kind() {
+		return fmt.Errorf("%v: expected a %v", path, t.Kind())
+	}
+
+	v.Set(value.Convert(t))
+	return nil
+}
+
+func decodeNode(raw interface{}, path string) (reflect.Value, error) {
+	obj, ok := raw.(map[string]interface{})
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("%v: expected an object", path)
+	}
+
+	kind, _ := obj["kind"].(string)
+	t, ok := nodeKinds[kind]
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("%v: unknown node kind %q", path, kind)
+	}
+
+	node := reflect.New(t)
+
+	for i := 0; i < t.NumField(); i++ {
+		key := fieldKey(t.Field(i).Name)
+
+		if err := decodeValue(obj[key], node.Elem().Field(i), path+"."+key); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+
+	return node, nil
+}
+
+func decodeToken(raw interface{}, v reflect.Value, path string) error {
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+
+	var t jsonToken
+	if err := json.Unmarshal(b, &t); err != nil || raw == nil {
+		return fmt.Errorf("%v: expected a token", path)
+	}
+
+	v.Set(reflect.ValueOf(token.NewToken(t.Type, t.Lexeme, t.Literal, t.Line, t.Column)))
+	return nil
+}
+
+func fieldKey(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+
+	return string(unicode.ToLower(r)) + name[size:]
+}
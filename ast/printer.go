@@ -0,0 +1,237 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Printer renders syntax trees as parenthesized, Lisp-style text.
+type Printer struct {
+	result string
+}
+
+func NewPrinter() *Printer {
+	return &Printer{}
+}
+
+func (p *Printer) Print(statements []Stmt) string {
+	lines := make([]string, len(statements))
+
+	for i, s := range statements {
+		lines[i] = p.PrintStmt(s)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (p *Printer) PrintStmt(s Stmt) string {
+	s.Accept(p)
+
+	return p.result
+}
+
+func (p *Printer) PrintExpr(e Expr) string {
+	s, _ := e.Accept(p)
+
+	return s.(string)
+}
+
+func (p *Printer) parenthesize(name string, parts ...interface{}) string {
+	var b strings.Builder
+
+	b.WriteString("(" + name)
+
+	for _, part := range parts {
+		switch v := part.(type) {
+		case nil:
+		case Expr:
+			b.WriteString(" " + p.PrintExpr(v))
+		case Stmt:
+			b.WriteString(" " + p.PrintStmt(v))
+		case []Expr:
+			for _, e := range v {
+				b.WriteString(" " + p.PrintExpr(e))
+			}
+		case []Stmt:
+			for _, s := range v {
+				b.WriteString(" " + p.PrintStmt(s))
+			}
+		default:
+			b.WriteString(fmt.Sprintf(" %v", v))
+		}
+	}
+
+	b.WriteString(")")
+
+	return b.String()
+}
+
+func (p *Printer) VisitAssignExpr(e *Assign) (interface{}, error) {
+	return p.parenthesize("=", e.Name.Lexeme(), e.Value), nil
+}
+
+func (p *Printer) VisitBinaryExpr(e *Binary) (interface{}, error) {
+	return p.parenthesize(e.Operator.Lexeme(), e.Left, e.Right), nil
+}
+
+func (p *Printer) VisitCallExpr(e *Call) (interface{}, error) {
+	parts := []interface{}{e.Callee, e.Arguments}
+	for _, arg := range e.NamedArguments {
+		parts = append(parts, arg.Name.Lexeme()+": "+p.PrintExpr(arg.Value))
+	}
+
+	return p.parenthesize("call", parts...), nil
+}
+
+func (p *Printer) VisitCoalesceExpr(e *Coalesce) (interface{}, error) {
+	return p.parenthesize(e.Operator.Lexeme(), e.Left, e.Right), nil
+}
+
+func (p *Printer) VisitConditionalExpr(e *Conditional) (interface{}, error) {
+	return p.parenthesize("?:", e.Condition, e.ThenBranch, e.ElseBranch), nil
+}
+
+func (p *Printer) VisitGroupingExpr(e *Grouping) (interface{}, error) {
+	return p.parenthesize("group", e.Expression), nil
+}
+
+func (p *Printer) VisitIncrementExpr(e *Increment) (interface{}, error) {
+	if e.Prefix {
+		return p.parenthesize(e.Operator.Lexeme()+"pre", e.Name.Lexeme()), nil
+	}
+
+	return p.parenthesize(e.Operator.Lexeme()+"post", e.Name.Lexeme()), nil
+}
+
+func (p *Printer) VisitInterpolationExpr(e *Interpolation) (interface{}, error) {
+	return p.parenthesize("interpolate", e.Parts), nil
+}
+
+func (p *Printer) VisitLiteralExpr(e *Literal) (interface{}, error) {
+	return formatLiteral(e.Value), nil
+}
+
+func (p *Printer) VisitLogicalExpr(e *Logical) (interface{}, error) {
+	return p.parenthesize(e.Operator.Lexeme(), e.Left, e.Right), nil
+}
+
+func (p *Printer) VisitUnaryExpr(e *Unary) (interface{}, error) {
+	return p.parenthesize(e.Operator.Lexeme(), e.Right), nil
+}
+
+func (p *Printer) VisitVariableExpr(e *Variable) (interface{}, error) {
+	return e.Name.Lexeme(), nil
+}
+
+func (p *Printer) VisitBlockStmt(s *Block) error {
+	p.result = p.parenthesize("block", s.Statements)
+	return nil
+}
+
+func (p *Printer) VisitExpressionStmt(s *Expression) error {
+	p.result = p.parenthesize(";", s.Exp)
+	return nil
+}
+
+func (p *Printer) VisitForInStmt(s *ForIn) error {
+	p.result = p.parenthesize("for-in "+s.Name.Lexeme(), s.Iterable, s.Body)
+	return nil
+}
+
+func (p *Printer) VisitFunctionStmt(s *Function) error {
+	params := make([]string, len(s.Params))
+	for i, param := range s.Params {
+		params[i] = param.Name.Lexeme() + annotation(param.Type)
+
+		if param.Rest {
+			params[i] = "..." + params[i]
+		}
+
+		if param.Default != nil {
+			params[i] += "=" + p.PrintExpr(param.Default)
+		}
+	}
+
+	p.result = p.parenthesize("fun "+s.Name.Lexeme()+annotation(s.ReturnType), "("+strings.Join(params, " ")+")", s.Body)
+	return nil
+}
+
+func (p *Printer) VisitIfStmt(s *If) error {
+	p.result = p.parenthesize("if", s.Condition, s.ThenBranch, s.ElseBranch)
+	return nil
+}
+
+func (p *Printer) VisitPrintStmt(s *Print) error {
+	p.result = p.parenthesize("print", s.Exp)
+	return nil
+}
+
+func (p *Printer) VisitReturnStmt(s *Return) error {
+	p.result = p.parenthesize("return", s.Value)
+	return nil
+}
+
+func (p *Printer) VisitSelectStmt(s *Select) error {
+	parts := []interface{}{}
+
+	for _, c := range s.Cases {
+		name := "case " + c.Operation.Lexeme()
+		if c.Name != nil {
+			name += " " + c.Name.Lexeme()
+		}
+
+		parts = append(parts, p.parenthesize(name, c.Channel, c.Value, c.Body))
+	}
+
+	if s.Default != nil {
+		parts = append(parts, p.parenthesize("default", s.Default))
+	}
+
+	p.result = p.parenthesize("select", parts...)
+	return nil
+}
+
+func (p *Printer) VisitSpawnStmt(s *Spawn) error {
+	p.result = p.parenthesize("spawn", s.Call)
+	return nil
+}
+
+func (p *Printer) VisitVarStmt(s *Var) error {
+	keyword := "var "
+	if s.Constant {
+		keyword = "const "
+	}
+
+	p.result = p.parenthesize(keyword+s.Name.Lexeme()+annotation(s.Type), s.Initializer)
+	return nil
+}
+
+func (p *Printer) VisitWhileStmt(s *While) error {
+	p.result = p.parenthesize("while", s.Condition, s.Body)
+	return nil
+}
+
+// annotation formats an optional type annotation as a ":type" suffix.
+func annotation(t *Type) string {
+	if t == nil {
+		return ""
+	}
+
+	return ":" + t.String()
+}
+
+func (p *Printer) VisitYieldStmt(s *Yield) error {
+	p.result = p.parenthesize("yield", s.Value)
+	return nil
+}
+
+func formatLiteral(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "nil"
+	case string:
+		return fmt.Sprintf("%q", v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
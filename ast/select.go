@@ -0,0 +1,14 @@
+package ast
+
+import "glox/token"
+
+// SelectCase is a `case` of a select statement. Operation is the `send` or
+// `receive` identifier; a send has a Value, and a receive may bind the
+// received value to Name for its body.
+type SelectCase struct {
+	Operation token.Token
+	Channel   Expr
+	Value     Expr
+	Name      *token.Token
+	Body      Stmt
+}
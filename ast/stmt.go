@@ -9,12 +9,16 @@ type Stmt interface {
 type VisitorStmt interface {
 	VisitBlockStmt(stmt *Block) error
 	VisitExpressionStmt(stmt *Expression) error
+	VisitForInStmt(stmt *ForIn) error
 	VisitFunctionStmt(stmt *Function) error
 	VisitIfStmt(stmt *If) error
 	VisitPrintStmt(stmt *Print) error
 	VisitReturnStmt(stmt *Return) error
+	VisitSelectStmt(stmt *Select) error
+	VisitSpawnStmt(stmt *Spawn) error
 	VisitVarStmt(stmt *Var) error
 	VisitWhileStmt(stmt *While) error
+	VisitYieldStmt(stmt *Yield) error
 }
 
 type Block struct {
@@ -43,14 +47,32 @@ func (e *Expression) Accept(v VisitorStmt) error {
 }
 
 
+type ForIn struct {
+	Name token.Token
+	Keyword token.Token
+	Iterable Expr
+	Body Stmt
+}
+
+func NewForIn(Name token.Token, Keyword token.Token, Iterable Expr, Body Stmt) *ForIn {
+	 return &ForIn{Name: Name, Keyword: Keyword, Iterable: Iterable, Body: Body}
+}
+
+func (e *ForIn) Accept(v VisitorStmt) error {
+	return v.VisitForInStmt(e)
+}
+
+
 type Function struct {
 	Name token.Token
-	Params []token.Token
+	Params []Param
+	ReturnType *Type
 	Body []Stmt
+	Doc string
 }
 
-func NewFunction(Name token.Token, Params []token.Token, Body []Stmt) *Function {
-	 return &Function{Name: Name, Params: Params, Body: Body}
+func NewFunction(Name token.Token, Params []Param, ReturnType *Type, Body []Stmt, Doc string) *Function {
+	 return &Function{Name: Name, Params: Params, ReturnType: ReturnType, Body: Body, Doc: Doc}
 }
 
 func (e *Function) Accept(v VisitorStmt) error {
@@ -100,13 +122,45 @@ func (e *Return) Accept(v VisitorStmt) error {
 }
 
 
+type Select struct {
+	Keyword token.Token
+	Cases []SelectCase
+	Default Stmt
+}
+
+func NewSelect(Keyword token.Token, Cases []SelectCase, Default Stmt) *Select {
+	 return &Select{Keyword: Keyword, Cases: Cases, Default: Default}
+}
+
+func (e *Select) Accept(v VisitorStmt) error {
+	return v.VisitSelectStmt(e)
+}
+
+
+type Spawn struct {
+	Keyword token.Token
+	Call *Call
+}
+
+func NewSpawn(Keyword token.Token, Call *Call) *Spawn {
+	 return &Spawn{Keyword: Keyword, Call: Call}
+}
+
+func (e *Spawn) Accept(v VisitorStmt) error {
+	return v.VisitSpawnStmt(e)
+}
+
+
 type Var struct {
 	Name token.Token
+	Type *Type
 	Initializer Expr
+	Doc string
+	Constant bool
 }
 
-func NewVar(Name token.Token, Initializer Expr) *Var {
-	 return &Var{Name: Name, Initializer: Initializer}
+func NewVar(Name token.Token, Type *Type, Initializer Expr, Doc string, Constant bool) *Var {
+	 return &Var{Name: Name, Type: Type, Initializer: Initializer, Doc: Doc, Constant: Constant}
 }
 
 func (e *Var) Accept(v VisitorStmt) error {
@@ -128,3 +182,17 @@ func (e *While) Accept(v VisitorStmt) error {
 }
 
 
+type Yield struct {
+	Keyword token.Token
+	Value Expr
+}
+
+func NewYield(Keyword token.Token, Value Expr) *Yield {
+	 return &Yield{Keyword: Keyword, Value: Value}
+}
+
+func (e *Yield) Accept(v VisitorStmt) error {
+	return v.VisitYieldStmt(e)
+}
+
+
@@ -0,0 +1,33 @@
+package ast
+
+import "glox/token"
+
+// Param is a function parameter with its optional type annotation and
+// default value. A Rest parameter collects the extra positional arguments.
+type Param struct {
+	Name    token.Token
+	Type    *Type
+	Default Expr
+	Rest    bool
+}
+
+// NamedArgument is an argument passed by name, as in `f(b: 3)`.
+type NamedArgument struct {
+	Name  token.Token
+	Value Expr
+}
+
+// Type is a type annotation such as `number` or `string?`. The interpreter
+// ignores annotations, they are only used by the type checker.
+type Type struct {
+	Name     token.Token
+	Nullable bool
+}
+
+func (t *Type) String() string {
+	if t.Nullable {
+		return t.Name.Lexeme() + "?"
+	}
+
+	return t.Name.Lexeme()
+}
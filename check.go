@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"glox/checker"
+	"glox/errors"
+	"glox/interpreter"
+	"glox/resolver"
+	"os"
+)
+
+func checkCommand(args []string) int {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: glox check file...")
+		fs.PrintDefaults()
+	}
+
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 64
+	}
+
+	for _, path := range fs.Args() {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 66
+		}
+
+		checkSource(string(b))
+	}
+
+	if errors.HadError() {
+		return 65
+	}
+
+	return 0
+}
+
+// checkSource reports the syntax, resolution and type errors of source.
+func checkSource(source string) {
+	hadError := errors.HadError()
+	errors.SetHadError(false)
+
+	statements := parseSource(source)
+	i := interpreter.NewInterpreter()
+
+	if !errors.HadError() {
+		resolver.NewResolver(&i).Resolve(statements)
+	}
+
+	if !errors.HadError() {
+		checker.NewChecker(&i).Check(statements)
+	}
+
+	errors.SetHadError(errors.HadError() || hadError)
+}
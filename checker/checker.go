@@ -0,0 +1,571 @@
+// Package checker implements `glox check`, a static pass over resolved
+// programs that verifies the optional type annotations.
+package checker
+
+import (
+	"fmt"
+	"glox/ast"
+	"glox/errors"
+	"glox/interpreter"
+	"glox/resolver"
+	"glox/token"
+)
+
+type Checker struct {
+	interp  *interpreter.Interpreter
+	scopes  *resolver.Stack[map[string]Type]
+	globals map[string]Type
+	// annotations and functions cache converted annotations, so that errors
+	// are reported once even though top-level declarations are visited twice.
+	annotations map[*ast.Type]Type
+	functions   map[*ast.Function]function
+	// result is the return type of the function being checked, nil at the
+	// top level.
+	result Type
+}
+
+// NewChecker creates a checker that knows the globals defined by i, such
+// as the native functions.
+func NewChecker(i *interpreter.Interpreter) *Checker {
+	s := resolver.Stack[map[string]Type]{}
+	c := &Checker{interp: i, scopes: s.New(), globals: map[string]Type{}, annotations: map[*ast.Type]Type{}, functions: map[*ast.Function]function{}}
+
+	for _, name := range i.Globals() {
+		c.globals[name] = valueType(i.Global(name))
+	}
+
+	return c
+}
+
+// Check reports type errors in statements, which must have been resolved.
+// Top-level functions and annotated variables are declared first so that
+// they can be used before their declaration.
+func (c *Checker) Check(statements []ast.Stmt) {
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case *ast.Function:
+			c.globals[s.Name.Lexeme()] = c.functionType(s)
+		case *ast.Var:
+			if s.Type != nil {
+				c.globals[s.Name.Lexeme()] = annotated{c.annotation(s.Type)}
+			}
+		}
+	}
+
+	c.statements(statements)
+}
+
+func (c *Checker) statements(statements []ast.Stmt) {
+	for _, s := range statements {
+		if s != nil {
+			s.Accept(c)
+		}
+	}
+}
+
+func (c *Checker) typeOf(e ast.Expr) Type {
+	t, _ := e.Accept(c)
+
+	return t.(Type)
+}
+
+// annotation converts a type annotation, reporting unknown type names.
+func (c *Checker) annotation(t *ast.Type) Type {
+	if t == nil {
+		return Any
+	}
+
+	if typ, ok := c.annotations[t]; ok {
+		return typ
+	}
+
+	typ, ok := basicTypes[t.Name.Lexeme()]
+	if !ok {
+		errors.Error(t.Name, fmt.Sprintf("Unknown type '%v'.", t.Name.Lexeme()))
+		typ = Any
+	}
+
+	if t.Nullable {
+		typ = makeNullable(typ)
+	}
+
+	c.annotations[t] = typ
+
+	return typ
+}
+
+func (c *Checker) functionType(f *ast.Function) function {
+	if fn, ok := c.functions[f]; ok {
+		return fn
+	}
+
+	fn := function{params: make([]Type, len(f.Params))}
+
+	for i, p := range f.Params {
+		fn.names = append(fn.names, p.Name.Lexeme())
+		fn.params[i] = c.annotation(p.Type)
+
+		switch {
+		case p.Rest:
+			fn.rest = true
+
+			if !assignable(fn.params[i], List) {
+				errors.Error(p.Name, "A rest parameter is a list.")
+			}
+
+			fn.params[i] = List
+		case p.Default == nil:
+			fn.required++
+		}
+	}
+
+	result := c.annotation(f.ReturnType)
+
+	if c.interp.IsGenerator(f) {
+		if !assignable(result, Gen) {
+			errors.Error(f.ReturnType.Name, "Functions containing a yield return a generator.")
+		}
+
+		result = Gen
+	}
+
+	fn.result = result
+	c.functions[f] = fn
+
+	return c.functions[f]
+}
+
+func (c *Checker) define(name token.Token, t Type) {
+	if c.scopes.IsEmpty() {
+		c.globals[name.Lexeme()] = t
+	} else {
+		(*c.scopes.Peek())[name.Lexeme()] = t
+	}
+}
+
+// annotated is the binding of a variable or a parameter declared with a
+// type annotation. Only these reject assignments of another type.
+type annotated struct {
+	Type
+}
+
+// scopeOf returns the scope that defines name, or nil for an unknown name.
+func (c *Checker) scopeOf(name token.Token) map[string]Type {
+	for i := c.scopes.Len() - 1; i >= 0; i-- {
+		if scope := *c.scopes.Get(i); scope[name.Lexeme()] != nil {
+			return scope
+		}
+	}
+
+	if _, ok := c.globals[name.Lexeme()]; ok {
+		return c.globals
+	}
+
+	return nil
+}
+
+func (c *Checker) lookup(name token.Token) Type {
+	scope := c.scopeOf(name)
+	if scope == nil {
+		return Any
+	}
+
+	if a, ok := scope[name.Lexeme()].(annotated); ok {
+		return a.Type
+	}
+
+	return scope[name.Lexeme()]
+}
+
+// numbers reports an error unless every operand is a number.
+func (c *Checker) numbers(operator token.Token, operands ...Type) {
+	for _, o := range operands {
+		if !assignable(Number, o) {
+			plural := ""
+			if len(operands) > 1 {
+				plural = "s"
+			}
+
+			errors.Error(operator, fmt.Sprintf("Operand%v must be number%v.", plural, plural))
+			return
+		}
+	}
+}
+
+func (c *Checker) VisitBlockStmt(s *ast.Block) error {
+	c.scopes.Push(map[string]Type{})
+	c.statements(s.Statements)
+	c.scopes.Pop()
+
+	return nil
+}
+
+func (c *Checker) VisitExpressionStmt(s *ast.Expression) error {
+	c.typeOf(s.Exp)
+
+	return nil
+}
+
+func (c *Checker) VisitForInStmt(s *ast.ForIn) error {
+	var element Type = Any
+
+	switch t := c.typeOf(s.Iterable); t {
+	case String:
+		element = String
+	case Range:
+		element = Number
+	case Any, List, Map, Gen:
+	default:
+		errors.Error(s.Keyword, fmt.Sprintf("Cannot iterate over %v.", t))
+	}
+
+	c.scopes.Push(map[string]Type{s.Name.Lexeme(): element})
+	s.Body.Accept(c)
+	c.scopes.Pop()
+
+	return nil
+}
+
+func (c *Checker) VisitFunctionStmt(s *ast.Function) error {
+	fn := c.functionType(s)
+	c.define(s.Name, fn)
+
+	enclosing := c.result
+	c.result = fn.result
+
+	if fn.result == Gen {
+		// Only a bare return can end a generator.
+		c.result = Nil
+	}
+
+	c.scopes.Push(map[string]Type{})
+	for i, p := range s.Params {
+		if p.Default != nil {
+			if t := c.typeOf(p.Default); !assignable(fn.params[i], t) {
+				errors.Error(p.Name, fmt.Sprintf("Default value of '%v' must be %v, got %v.", p.Name.Lexeme(), fn.params[i], t))
+			}
+		}
+
+		if p.Type != nil {
+			c.define(p.Name, annotated{fn.params[i]})
+		} else {
+			c.define(p.Name, fn.params[i])
+		}
+	}
+
+	c.statements(s.Body)
+	c.scopes.Pop()
+
+	c.result = enclosing
+
+	return nil
+}
+
+func (c *Checker) VisitIfStmt(s *ast.If) error {
+	c.typeOf(s.Condition)
+	s.ThenBranch.Accept(c)
+
+	if s.ElseBranch != nil {
+		s.ElseBranch.Accept(c)
+	}
+
+	return nil
+}
+
+func (c *Checker) VisitPrintStmt(s *ast.Print) error {
+	c.typeOf(s.Exp)
+
+	return nil
+}
+
+func (c *Checker) VisitReturnStmt(s *ast.Return) error {
+	var t Type = Nil
+	if s.Value != nil {
+		t = c.typeOf(s.Value)
+	}
+
+	if c.result != nil && !assignable(c.result, t) {
+		errors.Error(s.Keyword, fmt.Sprintf("Cannot return %v from a function returning %v.", t, c.result))
+	}
+
+	return nil
+}
+
+func (c *Checker) VisitSelectStmt(s *ast.Select) error {
+	for _, sc := range s.Cases {
+		if t := c.typeOf(sc.Channel); !assignable(Chan, t) {
+			errors.Error(sc.Operation, fmt.Sprintf("Cannot %v on %v, expected a channel.", sc.Operation.Lexeme(), t))
+		}
+
+		if sc.Value != nil {
+			c.typeOf(sc.Value)
+		}
+
+		if sc.Name == nil {
+			sc.Body.Accept(c)
+			continue
+		}
+
+		c.scopes.Push(map[string]Type{sc.Name.Lexeme(): Any})
+		sc.Body.Accept(c)
+		c.scopes.Pop()
+	}
+
+	if s.Default != nil {
+		s.Default.Accept(c)
+	}
+
+	return nil
+}
+
+func (c *Checker) VisitSpawnStmt(s *ast.Spawn) error {
+	c.typeOf(s.Call)
+
+	return nil
+}
+
+func (c *Checker) VisitVarStmt(s *ast.Var) error {
+	var t Type = Nil
+	if s.Initializer != nil {
+		t = c.typeOf(s.Initializer)
+	}
+
+	if s.Type == nil {
+		// Without an annotation the variable starts with the type of its
+		// initializer, unless it is nil, and is widened by assignments.
+		if t == Nil {
+			t = Any
+		}
+
+		c.define(s.Name, t)
+		return nil
+	}
+
+	declared := c.annotation(s.Type)
+
+	if s.Initializer == nil && !assignable(declared, Nil) {
+		errors.Error(s.Name, fmt.Sprintf("Variable '%v' of type %v must be initialized.", s.Name.Lexeme(), declared))
+	} else if !assignable(declared, t) {
+		errors.Error(s.Name, fmt.Sprintf("Cannot assign %v to '%v' of type %v.", t, s.Name.Lexeme(), declared))
+	}
+
+	c.define(s.Name, annotated{declared})
+
+	return nil
+}
+
+func (c *Checker) VisitYieldStmt(s *ast.Yield) error {
+	if s.Value != nil {
+		c.typeOf(s.Value)
+	}
+
+	return nil
+}
+
+func (c *Checker) VisitWhileStmt(s *ast.While) error {
+	c.typeOf(s.Condition)
+	s.Body.Accept(c)
+
+	return nil
+}
+
+func (c *Checker) VisitAssignExpr(e *ast.Assign) (interface{}, error) {
+	t := c.typeOf(e.Value)
+
+	scope := c.scopeOf(e.Name)
+	if scope == nil {
+		return t, nil
+	}
+
+	switch target := scope[e.Name.Lexeme()].(type) {
+	case annotated:
+		if !assignable(target.Type, t) {
+			errors.Error(e.Name, fmt.Sprintf("Cannot assign %v to '%v' of type %v.", t, e.Name.Lexeme(), target.Type))
+		}
+	default:
+		if !assignable(target, t) {
+			scope[e.Name.Lexeme()] = join(target, t)
+		}
+	}
+
+	return t, nil
+}
+
+func (c *Checker) VisitBinaryExpr(e *ast.Binary) (interface{}, error) {
+	left := c.typeOf(e.Left)
+	right := c.typeOf(e.Right)
+
+	switch e.Operator.Type() {
+	case token.EQUAL_EQUAL, token.BANG_EQUAL:
+		return Bool, nil
+
+	case token.GREATER, token.GREATER_EQUAL, token.LESS, token.LESS_EQUAL:
+		c.numbers(e.Operator, left, right)
+		return Bool, nil
+
+	case token.PLUS:
+		switch {
+		case left == String || right == String:
+			return String, nil
+		case left == Any || right == Any:
+			return Any, nil
+		case left == Number && right == Number:
+			return Number, nil
+		}
+
+		errors.Error(e.Operator, "Operands must be two numbers or include a string.")
+		return Any, nil
+	}
+
+	c.numbers(e.Operator, left, right)
+
+	return Number, nil
+}
+
+func (c *Checker) VisitCallExpr(e *ast.Call) (interface{}, error) {
+	callee := c.typeOf(e.Callee)
+
+	args := make([]Type, len(e.Arguments))
+	for i, arg := range e.Arguments {
+		args[i] = c.typeOf(arg)
+	}
+
+	named := make([]Type, len(e.NamedArguments))
+	for i, arg := range e.NamedArguments {
+		named[i] = c.typeOf(arg.Value)
+	}
+
+	switch fn := callee.(type) {
+	case function:
+		if fn.anySignature {
+			return Any, nil
+		}
+
+		c.arguments(fn, e, args, named)
+
+		return fn.result, nil
+
+	case basic:
+		if fn != Any {
+			errors.Error(e.Paren, "Can only call functions and classes.")
+		}
+	}
+
+	return Any, nil
+}
+
+// arguments checks the arguments of a call against the parameters of fn.
+func (c *Checker) arguments(fn function, e *ast.Call, args []Type, named []Type) {
+	fixed := fn.fixed()
+
+	if count := len(args) + len(named); (count > fixed && !fn.rest) || count < fn.required {
+		errors.Error(e.Paren, fmt.Sprintf("Expected %v arguments but got %v.", fn.arity(), count))
+		return
+	}
+
+	if len(named) > 0 && len(fn.names) == 0 {
+		errors.Error(e.NamedArguments[0].Name, "Native functions don't take named arguments.")
+		return
+	}
+
+	given := make([]bool, fixed)
+
+	for i, arg := range args {
+		if i >= fixed {
+			break
+		}
+
+		given[i] = true
+
+		if !assignable(fn.params[i], arg) {
+			errors.Error(e.Paren, fmt.Sprintf("Argument %v must be %v, got %v.", i+1, fn.params[i], arg))
+		}
+	}
+
+	for i, arg := range e.NamedArguments {
+		name := arg.Name.Lexeme()
+
+		j := 0
+		for j < fixed && fn.names[j] != name {
+			j++
+		}
+
+		switch {
+		case j == fixed:
+			errors.Error(arg.Name, fmt.Sprintf("Unknown argument '%v'.", name))
+		case given[j]:
+			errors.Error(arg.Name, fmt.Sprintf("Argument '%v' is given twice.", name))
+		default:
+			given[j] = true
+
+			if !assignable(fn.params[j], named[i]) {
+				errors.Error(arg.Name, fmt.Sprintf("Argument '%v' must be %v, got %v.", name, fn.params[j], named[i]))
+			}
+		}
+	}
+
+	for i := 0; i < fn.required; i++ {
+		if !given[i] {
+			errors.Error(e.Paren, fmt.Sprintf("Missing argument '%v'.", fn.names[i]))
+		}
+	}
+}
+
+func (c *Checker) VisitCoalesceExpr(e *ast.Coalesce) (interface{}, error) {
+	left := nonNullable(c.typeOf(e.Left))
+	right := c.typeOf(e.Right)
+
+	if left == Nil {
+		return right, nil
+	}
+
+	return join(left, right), nil
+}
+
+func (c *Checker) VisitConditionalExpr(e *ast.Conditional) (interface{}, error) {
+	c.typeOf(e.Condition)
+
+	return join(c.typeOf(e.ThenBranch), c.typeOf(e.ElseBranch)), nil
+}
+
+func (c *Checker) VisitGroupingExpr(e *ast.Grouping) (interface{}, error) {
+	return c.typeOf(e.Expression), nil
+}
+
+func (c *Checker) VisitIncrementExpr(e *ast.Increment) (interface{}, error) {
+	c.numbers(e.Operator, c.lookup(e.Name))
+
+	return Number, nil
+}
+
+func (c *Checker) VisitInterpolationExpr(e *ast.Interpolation) (interface{}, error) {
+	for _, part := range e.Parts {
+		c.typeOf(part)
+	}
+
+	return String, nil
+}
+
+func (c *Checker) VisitLiteralExpr(e *ast.Literal) (interface{}, error) {
+	return valueType(e.Value), nil
+}
+
+func (c *Checker) VisitLogicalExpr(e *ast.Logical) (interface{}, error) {
+	return join(c.typeOf(e.Left), c.typeOf(e.Right)), nil
+}
+
+func (c *Checker) VisitUnaryExpr(e *ast.Unary) (interface{}, error) {
+	right := c.typeOf(e.Right)
+
+	if e.Operator.Type() == token.BANG {
+		return Bool, nil
+	}
+
+	c.numbers(e.Operator, right)
+
+	return Number, nil
+}
+
+func (c *Checker) VisitVariableExpr(e *ast.Variable) (interface{}, error) {
+	return c.lookup(e.Name), nil
+}
@@ -0,0 +1,234 @@
+package checker
+
+import (
+	"fmt"
+	"glox/interpreter"
+	"strings"
+)
+
+// Type is the static type of an expression.
+type Type interface {
+	String() string
+}
+
+type basic string
+
+const (
+	Any    basic = "any"
+	Number basic = "number"
+	String basic = "string"
+	Bool   basic = "bool"
+	Nil    basic = "nil"
+	List   basic = "list"
+	Map    basic = "map"
+	Chan   basic = "channel"
+	Gen    basic = "generator"
+	Range  basic = "range"
+)
+
+func (b basic) String() string {
+	return string(b)
+}
+
+// nullable is a type whose values can also be nil, written `type?`.
+type nullable struct {
+	inner Type
+}
+
+func (n nullable) String() string {
+	return n.inner.String() + "?"
+}
+
+// function is the type of a callable. Only its first required parameters
+// must be given, and when rest is set its last parameter is a list of the
+// extra positional arguments. names is empty for native functions. The
+// `fun` annotation accepts any callable, whatever its signature.
+type function struct {
+	params       []Type
+	names        []string
+	required     int
+	rest         bool
+	result       Type
+	anySignature bool
+}
+
+// fixed is the number of parameters that take a single argument.
+func (f function) fixed() int {
+	if f.rest {
+		return len(f.params) - 1
+	}
+
+	return len(f.params)
+}
+
+// arity describes the number of arguments the function takes.
+func (f function) arity() string {
+	switch {
+	case f.rest:
+		return fmt.Sprintf("at least %v", f.required)
+	case f.required < f.fixed():
+		return fmt.Sprintf("%v to %v", f.required, f.fixed())
+	}
+
+	return fmt.Sprint(f.required)
+}
+
+func (f function) String() string {
+	if f.anySignature {
+		return "fun"
+	}
+
+	params := make([]string, len(f.params))
+	for i, p := range f.params {
+		params[i] = p.String()
+	}
+
+	if f.rest {
+		params[len(params)-1] = "..." + params[len(params)-1]
+	}
+
+	return "fun(" + strings.Join(params, ", ") + "): " + f.result.String()
+}
+
+var basicTypes = map[string]Type{
+	"any":       Any,
+	"number":    Number,
+	"string":    String,
+	"bool":      Bool,
+	"nil":       Nil,
+	"list":      List,
+	"map":       Map,
+	"channel":   Chan,
+	"generator": Gen,
+	"range":     Range,
+	"fun":       function{anySignature: true},
+}
+
+// assignable reports whether a value of type from can be stored where a
+// value of type to is expected.
+func assignable(to Type, from Type) bool {
+	if to == Any || from == Any {
+		return true
+	}
+
+	if n, ok := to.(nullable); ok {
+		if from == Nil {
+			return true
+		}
+
+		if f, ok := from.(nullable); ok {
+			from = f.inner
+		}
+
+		return assignable(n.inner, from)
+	}
+
+	if _, ok := from.(nullable); ok {
+		return false
+	}
+
+	if t, ok := to.(function); ok {
+		f, ok := from.(function)
+		if !ok {
+			return false
+		}
+
+		if t.anySignature || f.anySignature {
+			return true
+		}
+
+		if len(t.params) != len(f.params) || !assignable(t.result, f.result) {
+			return false
+		}
+
+		for i := range t.params {
+			if !assignable(f.params[i], t.params[i]) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return to == from
+}
+
+// join returns the type of a value that is either of type a or of type b.
+func join(a Type, b Type) Type {
+	switch {
+	case a.String() == b.String():
+		return a
+	case a == Any || b == Any:
+		return Any
+	case a == Nil:
+		return makeNullable(b)
+	case b == Nil:
+		return makeNullable(a)
+	}
+
+	if n, ok := a.(nullable); ok && assignable(n, b) {
+		return a
+	}
+
+	if n, ok := b.(nullable); ok && assignable(n, a) {
+		return b
+	}
+
+	return Any
+}
+
+func makeNullable(t Type) Type {
+	if _, ok := t.(nullable); ok || t == Any || t == Nil {
+		return t
+	}
+
+	return nullable{t}
+}
+
+func nonNullable(t Type) Type {
+	if n, ok := t.(nullable); ok {
+		return n.inner
+	}
+
+	return t
+}
+
+// valueType returns the type of a runtime value, used for literals and
+// the globals defined by the interpreter.
+func valueType(v interface{}) Type {
+	switch v := v.(type) {
+	case nil:
+		return Nil
+	case float64:
+		return Number
+	case string:
+		return String
+	case bool:
+		return Bool
+	case *interpreter.List:
+		return List
+	case *interpreter.Map:
+		return Map
+	case *interpreter.Channel:
+		return Chan
+	case *interpreter.Generator:
+		return Gen
+	case *interpreter.Range:
+		return Range
+	case interpreter.Callable:
+		sig := v.Signature()
+
+		params := make([]Type, sig.Required+sig.Optional)
+		for i := range params {
+			params[i] = Any
+		}
+
+		if sig.Rest {
+			params = append(params, List)
+		}
+
+		return function{params: params, names: sig.Params, required: sig.Required, rest: sig.Rest, result: Any}
+	}
+
+	return Any
+}
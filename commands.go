@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"glox/ast"
+	"glox/errors"
+	"glox/interpreter"
+	"glox/parser"
+	"glox/scanner"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+type command struct {
+	usage string
+	help  string
+	run   func(arg string)
+}
+
+var commands map[string]command
+
+// session holds the inputs that ran without errors, in order, for :save.
+var session []string
+
+func init() {
+	commands = map[string]command{
+		"help":   {":help", "list the available commands", helpCommand},
+		"env":    {":env", "list global bindings", envCommand},
+		"ast":    {":ast <expr>", "show the parsed tree of an expression", astCommand},
+		"tokens": {":tokens <src>", "show the scanner output", tokensMetaCommand},
+		"load":   {":load <file>", "execute a file into the session", loadCommand},
+		"save":   {":save <file>", "write the successful inputs of the session to a file", saveCommand},
+		"reset":  {":reset", "start over with a fresh interpreter", resetCommand},
+		"time":   {":time <stmt>", "measure the execution of a statement", timeCommand},
+	}
+}
+
+func runCommand(input string) {
+	name, arg, _ := strings.Cut(strings.TrimSpace(input[1:]), " ")
+	arg = strings.TrimSpace(arg)
+
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Printf("Unknown command ':%v'. Type :help for a list of commands.\n", name)
+		return
+	}
+
+	cmd.run(arg)
+}
+
+func runRecorded(source string) {
+	run(source)
+
+	if !errors.HadError() && !errors.HadRuntimeError() {
+		session = append(session, source)
+	}
+}
+
+func helpCommand(_ string) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Printf("  %-16v %v\n", commands[name].usage, commands[name].help)
+	}
+}
+
+func envCommand(_ string) {
+	for _, name := range interp.Globals() {
+		fmt.Printf("%v = %v\n", name, interpreter.Stringify(interp.Global(name)))
+	}
+}
+
+func astCommand(arg string) {
+	s := scanner.NewScanner(arg)
+	p := parser.NewParser(s.ScanTokens())
+	expr := p.ParseExpression()
+
+	if errors.HadError() {
+		return
+	}
+
+	fmt.Println(ast.NewPrinter().PrintExpr(expr))
+}
+
+func tokensMetaCommand(arg string) {
+	s := scanner.NewScanner(arg)
+
+	for _, t := range s.ScanTokens() {
+		fmt.Println(t)
+	}
+}
+
+func loadCommand(arg string) {
+	b, err := os.ReadFile(arg)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	runRecorded(string(b))
+}
+
+func saveCommand(arg string) {
+	if arg == "" {
+		fmt.Println("Usage: :save <file>")
+		return
+	}
+
+	content := strings.Join(session, "\n")
+	if content != "" {
+		content += "\n"
+	}
+
+	if err := os.WriteFile(arg, []byte(content), 0644); err != nil {
+		fmt.Println(err)
+	}
+}
+
+func resetCommand(_ string) {
+	interp = newInterpreter()
+	interp.SetEcho(true)
+	session = nil
+}
+
+func timeCommand(arg string) {
+	start := time.Now()
+	runRecorded(arg)
+
+	fmt.Printf("Elapsed: %v\n", time.Since(start))
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"glox/docgen"
+	"glox/errors"
+	"os"
+)
+
+func docCommand(args []string) int {
+	fs := flag.NewFlagSet("doc", flag.ExitOnError)
+	out := fs.String("out", "docs", "output directory")
+
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: glox doc [--out=dir] dir")
+		fs.PrintDefaults()
+	}
+
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 64
+	}
+
+	files, err := docgen.Collect(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 66
+	}
+
+	if err := docgen.Generate(files, *out); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 73
+	}
+
+	if errors.HadError() {
+		return 65
+	}
+
+	return 0
+}
@@ -0,0 +1,229 @@
+package docgen
+
+import (
+	"glox/ast"
+	"glox/errors"
+	"glox/parser"
+	"glox/scanner"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type Kind string
+
+const (
+	FunctionKind Kind = "function"
+	VariableKind Kind = "variable"
+	ConstantKind Kind = "constant"
+)
+
+// Symbol is a documented top-level declaration.
+type Symbol struct {
+	Name   string
+	Kind   Kind
+	Params []string
+	// Type is the annotated type of a variable or the return type of a
+	// function, if any.
+	Type string
+	Doc  string
+	Line int
+}
+
+func (s Symbol) Signature() string {
+	signature := "var " + s.Name
+	switch s.Kind {
+	case FunctionKind:
+		signature = "fun " + s.Name + "(" + strings.Join(s.Params, ", ") + ")"
+	case ConstantKind:
+		signature = "const " + s.Name
+	}
+
+	if s.Type != "" {
+		signature += ": " + s.Type
+	}
+
+	return signature
+}
+
+// File holds the symbols declared at the top level of a Lox source file.
+type File struct {
+	// Path is relative to the documented directory, with forward slashes.
+	Path    string
+	Symbols []Symbol
+}
+
+// Collect parses every .lox file under root. Syntax errors are reported
+// through the errors package and the files containing them are skipped.
+func Collect(root string) ([]File, error) {
+	files := []File{}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".lox" {
+			return err
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		hadError := errors.HadError()
+		errors.SetHadError(false)
+
+		s := scanner.NewScanner(string(b))
+		p := parser.NewParser(s.ScanTokens())
+		statements := p.Parse()
+
+		if !errors.HadError() {
+			files = append(files, File{Path: filepath.ToSlash(rel), Symbols: symbols(statements)})
+		}
+
+		errors.SetHadError(errors.HadError() || hadError)
+
+		return nil
+	})
+
+	return files, err
+}
+
+func symbols(statements []ast.Stmt) []Symbol {
+	symbols := []Symbol{}
+	printer := ast.NewPrinter()
+
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case *ast.Function:
+			params := make([]string, len(s.Params))
+			for i, p := range s.Params {
+				params[i] = p.Name.Lexeme()
+
+				if p.Rest {
+					params[i] = "..." + params[i]
+				}
+
+				if p.Type != nil {
+					params[i] += ": " + p.Type.String()
+				}
+
+				if p.Default != nil {
+					params[i] += " = " + printer.PrintExpr(p.Default)
+				}
+			}
+
+			symbols = append(symbols, Symbol{Name: s.Name.Lexeme(), Kind: FunctionKind, Params: params, Type: typeName(s.ReturnType), Doc: s.Doc, Line: s.Name.Line()})
+
+		case *ast.Var:
+			kind := VariableKind
+			if s.Constant {
+				kind = ConstantKind
+			}
+
+			symbols = append(symbols, Symbol{Name: s.Name.Lexeme(), Kind: kind, Type: typeName(s.Type), Doc: s.Doc, Line: s.Name.Line()})
+		}
+	}
+
+	sort.SliceStable(symbols, func(i, j int) bool {
+		return symbols[i].Name < symbols[j].Name
+	})
+
+	return symbols
+}
+
+func typeName(t *ast.Type) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.String()
+}
+
+// reference matches the [name] cross-references written in doc comments.
+var reference = regexp.MustCompile(`\[([\p{L}_][\p{L}\p{N}_]*)\]`)
+
+// site knows where every symbol is documented, to resolve cross-references.
+type site struct {
+	files []File
+	// pages maps a symbol name to the page, without extension, of the
+	// first file that declares it.
+	pages map[string]string
+}
+
+func newSite(files []File) *site {
+	s := &site{files: files, pages: map[string]string{}}
+
+	for _, f := range files {
+		for _, sym := range f.Symbols {
+			if _, ok := s.pages[sym.Name]; !ok {
+				s.pages[sym.Name] = page(f.Path)
+			}
+		}
+	}
+
+	return s
+}
+
+// link returns the URL of the documentation of name, relative to the page
+// from, or false if name is not a documented symbol.
+func (s *site) link(from string, name string, ext string) (string, bool) {
+	target, ok := s.pages[name]
+	if !ok {
+		return "", false
+	}
+
+	rel, err := filepath.Rel(filepath.Dir(filepath.FromSlash(from)), filepath.FromSlash(target))
+	if err != nil {
+		return "", false
+	}
+
+	return filepath.ToSlash(rel) + ext + "#" + name, true
+}
+
+func page(path string) string {
+	return strings.TrimSuffix(path, ".lox")
+}
+
+// Generate writes the Markdown and HTML documentation of files, an index
+// of both and a search index into dir.
+func Generate(files []File, dir string) error {
+	s := newSite(files)
+	out := map[string]string{
+		"index.md":   s.markdownIndex(),
+		"index.html": s.htmlIndex(),
+	}
+
+	for _, f := range files {
+		out[page(f.Path)+".md"] = s.markdownFile(f)
+		out[page(f.Path)+".html"] = s.htmlFile(f)
+	}
+
+	index, err := s.searchIndex()
+	if err != nil {
+		return err
+	}
+
+	out["search-index.json"] = string(index)
+	out["search-index.js"] = "var searchIndex = " + string(index) + ";\n"
+
+	for name, content := range out {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
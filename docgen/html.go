@@ -0,0 +1,196 @@
+package docgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"path/filepath"
+	"strings"
+)
+
+const style = `body { font-family: sans-serif; max-width: 50em; margin: 2em auto; padding: 0 1em; line-height: 1.5; }
+pre, code { background: #f4f4f4; border-radius: 3px; }
+pre { padding: .5em; }
+.decl { color: #666; font-size: .9em; }
+#results li { margin: .2em 0; }`
+
+const searchScript = `
+var input = document.getElementById("search");
+var results = document.getElementById("results");
+input.addEventListener("input", function () {
+  var q = input.value.toLowerCase();
+  results.innerHTML = "";
+  if (q === "") return;
+  searchIndex.filter(function (s) {
+    return s.name.toLowerCase().indexOf(q) >= 0 || s.summary.toLowerCase().indexOf(q) >= 0;
+  }).forEach(function (s) {
+    var li = document.createElement("li");
+    var a = document.createElement("a");
+    a.href = s.url;
+    a.textContent = s.signature;
+    li.appendChild(a);
+    li.appendChild(document.createTextNode(" " + s.file));
+    results.appendChild(li);
+  });
+});
+`
+
+func htmlPage(title string, root string, body string, scripts ...string) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%v</title>\n<style>\n%v\n</style>\n</head>\n<body>\n", html.EscapeString(title), style)
+
+	if root != "" {
+		fmt.Fprintf(&b, "<p><a href=\"%v\">Index</a></p>\n", root)
+	}
+
+	b.WriteString(body)
+
+	for _, s := range scripts {
+		b.WriteString(s)
+	}
+
+	b.WriteString("</body>\n</html>\n")
+
+	return b.String()
+}
+
+func (s *site) htmlIndex() string {
+	var b strings.Builder
+
+	b.WriteString("<h1>Documentation</h1>\n<input id=\"search\" type=\"search\" placeholder=\"Search symbols\">\n<ul id=\"results\"></ul>\n")
+
+	for _, f := range s.files {
+		fmt.Fprintf(&b, "<h2><a href=\"%v.html\">%v</a></h2>\n<ul>\n", page(f.Path), html.EscapeString(f.Path))
+
+		for _, sym := range f.Symbols {
+			fmt.Fprintf(&b, "<li><a href=\"%v.html#%v\"><code>%v</code></a>", page(f.Path), sym.Name, html.EscapeString(sym.Signature()))
+
+			if summary := summary(sym.Doc); summary != "" {
+				b.WriteString(": " + s.htmlText("index.html", summary))
+			}
+
+			b.WriteString("</li>\n")
+		}
+
+		b.WriteString("</ul>\n")
+	}
+
+	return htmlPage("Documentation", "", b.String(),
+		"<script src=\"search-index.js\"></script>\n",
+		"<script>"+searchScript+"</script>\n")
+}
+
+func (s *site) htmlFile(f File) string {
+	var b strings.Builder
+	from := page(f.Path) + ".html"
+
+	fmt.Fprintf(&b, "<h1>%v</h1>\n", html.EscapeString(f.Path))
+
+	for _, kind := range []Kind{FunctionKind, ConstantKind, VariableKind} {
+		title := map[Kind]string{FunctionKind: "Functions", ConstantKind: "Constants", VariableKind: "Variables"}[kind]
+		written := false
+
+		for _, sym := range f.Symbols {
+			if sym.Kind != kind {
+				continue
+			}
+
+			if !written {
+				fmt.Fprintf(&b, "<h2>%v</h2>\n", title)
+				written = true
+			}
+
+			fmt.Fprintf(&b, "<h3 id=\"%v\">%v</h3>\n<pre><code>%v</code></pre>\n", sym.Name, html.EscapeString(sym.Name), html.EscapeString(sym.Signature()))
+
+			if sym.Doc != "" {
+				b.WriteString(s.htmlText(from, sym.Doc) + "\n")
+			}
+
+			fmt.Fprintf(&b, "<p class=\"decl\">Declared at line %v.</p>\n", sym.Line)
+		}
+	}
+
+	return htmlPage(f.Path, relativeRoot(f.Path), b.String())
+}
+
+// htmlText renders a doc comment as paragraphs separated by blank lines,
+// with `code` spans and [name] references turned into links.
+func (s *site) htmlText(from string, text string) string {
+	paragraphs := []string{}
+
+	for _, p := range strings.Split(text, "\n\n") {
+		p = html.EscapeString(strings.TrimSpace(p))
+		if p == "" {
+			continue
+		}
+
+		p = codeSpan(p)
+		p = reference.ReplaceAllStringFunc(p, func(ref string) string {
+			name := ref[1 : len(ref)-1]
+
+			if url, ok := s.link(from, name, ".html"); ok {
+				return "<a href=\"" + url + "\"><code>" + name + "</code></a>"
+			}
+
+			return ref
+		})
+
+		paragraphs = append(paragraphs, "<p>"+p+"</p>")
+	}
+
+	return strings.Join(paragraphs, "\n")
+}
+
+func codeSpan(text string) string {
+	parts := strings.Split(text, "`")
+
+	var b strings.Builder
+	for i, p := range parts {
+		switch {
+		case i%2 == 0:
+			b.WriteString(p)
+		case i == len(parts)-1:
+			// unmatched backquote
+			b.WriteString("`" + p)
+		default:
+			b.WriteString("<code>" + p + "</code>")
+		}
+	}
+
+	return b.String()
+}
+
+func relativeRoot(path string) string {
+	depth := strings.Count(filepath.ToSlash(path), "/")
+
+	return strings.Repeat("../", depth) + "index.html"
+}
+
+type searchEntry struct {
+	Name      string `json:"name"`
+	Kind      Kind   `json:"kind"`
+	Signature string `json:"signature"`
+	File      string `json:"file"`
+	URL       string `json:"url"`
+	Summary   string `json:"summary"`
+}
+
+func (s *site) searchIndex() ([]byte, error) {
+	entries := []searchEntry{}
+
+	for _, f := range s.files {
+		for _, sym := range f.Symbols {
+			entries = append(entries, searchEntry{
+				Name:      sym.Name,
+				Kind:      sym.Kind,
+				Signature: sym.Signature(),
+				File:      f.Path,
+				URL:       page(f.Path) + ".html#" + sym.Name,
+				Summary:   summary(sym.Doc),
+			})
+		}
+	}
+
+	return json.Marshal(entries)
+}
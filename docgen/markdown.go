@@ -0,0 +1,89 @@
+package docgen
+
+import (
+	"fmt"
+	"strings"
+)
+
+func (s *site) markdownIndex() string {
+	var b strings.Builder
+
+	b.WriteString("# Documentation\n")
+
+	for _, f := range s.files {
+		fmt.Fprintf(&b, "\n## [%v](%v.md)\n\n", f.Path, page(f.Path))
+
+		for _, sym := range f.Symbols {
+			fmt.Fprintf(&b, "- [`%v`](%v.md#%v)", sym.Signature(), page(f.Path), sym.Name)
+
+			if summary := summary(sym.Doc); summary != "" {
+				b.WriteString(": " + s.markdownText("index.md", summary))
+			}
+
+			b.WriteString("\n")
+		}
+	}
+
+	return b.String()
+}
+
+func (s *site) markdownFile(f File) string {
+	var b strings.Builder
+	from := page(f.Path) + ".md"
+
+	fmt.Fprintf(&b, "# %v\n", f.Path)
+
+	for _, kind := range []Kind{FunctionKind, ConstantKind, VariableKind} {
+		title := map[Kind]string{FunctionKind: "Functions", ConstantKind: "Constants", VariableKind: "Variables"}[kind]
+		written := false
+
+		for _, sym := range f.Symbols {
+			if sym.Kind != kind {
+				continue
+			}
+
+			if !written {
+				fmt.Fprintf(&b, "\n## %v\n", title)
+				written = true
+			}
+
+			fmt.Fprintf(&b, "\n<a id=\"%v\"></a>\n### %v\n\n```lox\n%v\n```\n", sym.Name, sym.Name, sym.Signature())
+
+			if len(sym.Params) > 0 {
+				b.WriteString("\nParameters: " + "`" + strings.Join(sym.Params, "`, `") + "`\n")
+			}
+
+			if sym.Doc != "" {
+				b.WriteString("\n" + s.markdownText(from, sym.Doc) + "\n")
+			}
+
+			fmt.Fprintf(&b, "\n_Declared at line %v._\n", sym.Line)
+		}
+	}
+
+	return b.String()
+}
+
+// markdownText turns the [name] references of a doc comment into links.
+func (s *site) markdownText(from string, text string) string {
+	return reference.ReplaceAllStringFunc(text, func(ref string) string {
+		name := ref[1 : len(ref)-1]
+
+		if url, ok := s.link(from, name, ".md"); ok {
+			return "[`" + name + "`](" + url + ")"
+		}
+
+		return ref
+	})
+}
+
+// summary returns the first sentence of a doc comment.
+func summary(doc string) string {
+	doc = strings.Join(strings.Fields(doc), " ")
+
+	if i := strings.Index(doc, ". "); i >= 0 {
+		return doc[:i+1]
+	}
+
+	return doc
+}
@@ -3,25 +3,61 @@ package environement
 import (
 	"glox/errors"
 	"glox/token"
+	"sort"
+	"sync"
 )
 
+// Env is safe for concurrent use, since spawned tasks share the globals and
+// the environments captured by closures.
 type Env struct {
+	mu        sync.RWMutex
 	values    map[string]interface{}
+	// constants holds the names defined with DefineConstant.
+	constants map[string]bool
 	enclosing *Env
 }
 
 func NewEnvironement(enclosing *Env) *Env {
-	env := &Env{values: map[string]interface{}{}, enclosing: enclosing}
+	env := &Env{values: map[string]interface{}{}, constants: map[string]bool{}, enclosing: enclosing}
 
 	return env
 }
 
 func (e *Env) Define(name string, value interface{}) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
 	e.values[name] = value
+	delete(e.constants, name)
+}
+
+// DefineConstant defines a variable that can't be assigned afterwards.
+func (e *Env) DefineConstant(name string, value interface{}) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.values[name] = value
+	e.constants[name] = true
+}
+
+// Names returns the sorted names defined directly in this environment.
+func (e *Env) Names() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	names := make([]string, 0, len(e.values))
+
+	for name := range e.values {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
 }
 
 func (e *Env) Get(name token.Token) (interface{}, error) {
-	if val, ok := e.values[name.Lexeme()]; ok {
+	if val, ok := e.lookup(name.Lexeme()); ok {
 		return val, nil
 	}
 
@@ -33,23 +69,50 @@ func (e *Env) Get(name token.Token) (interface{}, error) {
 }
 
 func (e *Env) GetAt(distance int, name string) (interface{}, error) {
-	return e.ancestor(distance).values[name], nil
+	val, _ := e.ancestor(distance).lookup(name)
+
+	return val, nil
+}
+
+func (e *Env) lookup(name string) (interface{}, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	val, ok := e.values[name]
+	return val, ok
+}
+
+// set assigns name if it is defined directly in this environment, and
+// fails if it is a constant.
+func (e *Env) set(name token.Token, value interface{}) (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, ok := e.values[name.Lexeme()]; !ok {
+		return false, nil
+	}
+
+	if e.constants[name.Lexeme()] {
+		return true, errors.NewRuntimeErr(name, "Can't assign to constant '"+name.Lexeme()+"'.")
+	}
+
+	e.values[name.Lexeme()] = value
+	return true, nil
 }
 
 func (e *Env) ancestor(distance int) *Env {
     env := e
 
     for i := 0; i < distance; i++ {
-        env = e.enclosing
+        env = env.enclosing
     }
 
     return env
 }
 
 func (e *Env) Assign(name token.Token, value interface{}) error {
-	if _, ok := e.values[name.Lexeme()]; ok {
-		e.values[name.Lexeme()] = value
-		return nil
+	if ok, err := e.set(name, value); ok {
+		return err
 	}
 
 	if e.enclosing != nil {
@@ -59,6 +122,14 @@ func (e *Env) Assign(name token.Token, value interface{}) error {
 	return errors.NewRuntimeErr(name, "Undefined variable '"+name.Lexeme()+"'.")
 }
 
-func (e *Env) AssignAt(distance int, name token.Token, value interface{}) {
-    e.ancestor(distance).values[name.Lexeme()] = value
+// AssignAt assigns a variable the resolver found distance environments up.
+// A variable missing from there means the resolver and the interpreter
+// disagree on the scopes, which is reported rather than losing the value.
+func (e *Env) AssignAt(distance int, name token.Token, value interface{}) error {
+    ok, err := e.ancestor(distance).set(name, value)
+    if !ok {
+        return errors.NewRuntimeErr(name, "Internal error: '"+name.Lexeme()+"' is not defined in its resolved scope.")
+    }
+
+    return err
 }
@@ -3,18 +3,71 @@ package errors
 import (
 	"fmt"
 	"glox/token"
+	"io"
+	"os"
+	"sync"
 )
 
-var HadError = false
-var HadRuntimeError = false
+var hadError = false
+var hadRuntimeError = false
+
+var output io.Writer = os.Stdout
+
+// mu serializes the reports of concurrently running tasks, and guards the
+// error flags they set.
+var mu sync.Mutex
+
+// HadError reports whether a syntax or resolution error was reported.
+func HadError() bool {
+    mu.Lock()
+    defer mu.Unlock()
+
+    return hadError
+}
+
+// HadRuntimeError reports whether a runtime error was reported.
+func HadRuntimeError() bool {
+    mu.Lock()
+    defer mu.Unlock()
+
+    return hadRuntimeError
+}
+
+// SetHadError sets the flag returned by HadError, so that a caller can
+// check a source on its own and restore the previous state afterwards.
+func SetHadError(v bool) {
+    mu.Lock()
+    defer mu.Unlock()
+
+    hadError = v
+}
+
+// Reset clears both error flags.
+func Reset() {
+    mu.Lock()
+    defer mu.Unlock()
+
+    hadError, hadRuntimeError = false, false
+}
+
+// SetOutput redirects error reports to w and returns the previous writer.
+func SetOutput(w io.Writer) io.Writer {
+    prev := output
+    output = w
+
+    return prev
+}
 
 func ErrorAt(line int, message string) {
     report(line, "", message)
 }
 
 func report(line int, where string, message string) {
-    fmt.Printf("[line %v] Error%v: %v\n", line, where, message)
-    HadError = true
+    mu.Lock()
+    defer mu.Unlock()
+
+    fmt.Fprintf(output, "[line %v] Error%v: %v\n", line, where, message)
+    hadError = true
 }
 
 func Error(t token.Token, message string) {
@@ -26,6 +79,9 @@ func Error(t token.Token, message string) {
 }
 
 func RuntimeError(err error) {
-    fmt.Println(err.Error())
-    HadRuntimeError = true
+    mu.Lock()
+    defer mu.Unlock()
+
+    fmt.Fprintln(output, err.Error())
+    hadRuntimeError = true
 }
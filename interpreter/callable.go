@@ -2,5 +2,5 @@ package interpreter
 
 type Callable interface {
     Call(*Interpreter, []interface{}) (interface{}, error)
-    Arity() int
+    Signature() Signature
 }
@@ -0,0 +1,22 @@
+package interpreter
+
+import "sync"
+
+// Channel is the runtime value of a Lox channel, used by spawned tasks to
+// communicate.
+type Channel struct {
+	ch chan interface{}
+}
+
+func (c *Channel) String() string {
+	return "<channel>"
+}
+
+// WaitGroup is the runtime value of a Lox wait group.
+type WaitGroup struct {
+	wg sync.WaitGroup
+}
+
+func (w *WaitGroup) String() string {
+	return "<wait group>"
+}
@@ -0,0 +1,53 @@
+package interpreter
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the source of time of the time natives. Hosts can replace the
+// system clock with SetClock, for instance with a FakeClock in tests.
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+// FakeClock is a Clock whose time only moves when Sleep is called.
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *FakeClock) Sleep(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// SetClock makes the time natives use c, and restarts the timer.
+func (i *Interpreter) SetClock(c Clock) {
+	i.clock = c
+	i.started = c.Now()
+}
@@ -6,32 +6,73 @@ import (
 )
 
 type Function struct {
-    declaration ast.Function
+    declaration *ast.Function
     closure *environement.Env
 }
 
-func NewFunction(declaration ast.Function, closure *environement.Env) Function {
+func NewFunction(declaration *ast.Function, closure *environement.Env) Function {
     return Function{declaration: declaration, closure: closure}
 }
 
+// Call runs the function, or returns a Generator over its body if it
+// contains a yield.
 func (f Function) Call(i *Interpreter, args []interface{}) (interface{}, error) {
-    env := environement.NewEnvironement(f.closure)
-
-    for j, p := range f.declaration.Params {
-        env.Define(p.Lexeme(), args[j])
+    if i.IsGenerator(f.declaration) {
+        return newGenerator(i, f, args), nil
     }
 
-    err := i.executeBlock(f.declaration.Body, env)
+    return f.run(i, args)
+}
+
+func (f Function) run(i *Interpreter, args []interface{}) (interface{}, error) {
+    for {
+        env := environement.NewEnvironement(f.closure)
 
-    if val, ok := err.(Return); ok {
-        return val.value, nil
-    }
+        for j, p := range f.declaration.Params {
+            if args[j] == absent {
+                val, err := i.evaluateIn(p.Default, env)
+                if err != nil {
+                    return nil, err
+                }
+
+                args[j] = val
+            }
+
+            env.Define(p.Name.Lexeme(), args[j])
+        }
+
+        err := i.executeBlock(f.declaration.Body, env)
 
-    return nil, err
+        if tail, ok := err.(TailCall); ok {
+            f, args = tail.function, tail.args
+            continue
+        }
+
+        if val, ok := err.(Return); ok {
+            return val.value, nil
+        }
+
+        return nil, err
+    }
 }
 
-func (f Function) Arity() int {
-    return len(f.declaration.Params)
+func (f Function) Signature() Signature {
+    sig := Signature{}
+
+    for _, p := range f.declaration.Params {
+        sig.Params = append(sig.Params, p.Name.Lexeme())
+
+        switch {
+        case p.Rest:
+            sig.Rest = true
+        case p.Default != nil:
+            sig.Optional++
+        default:
+            sig.Required++
+        }
+    }
+
+    return sig
 }
 
 func (f Function) String() string {
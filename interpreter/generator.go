@@ -0,0 +1,161 @@
+package interpreter
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Generator is the value returned by calling a function that contains a
+// yield. Its body runs on its own goroutine, one step at a time: each call
+// to advance resumes it until the next yield or the end of the function.
+//
+// The goroutine only references the coroutine, not the Generator, so that
+// an unreachable generator can be finalized, which stops its goroutine.
+type Generator struct {
+	mu sync.Mutex
+	*coroutine
+	// peeked holds a value already produced by hasNext.
+	peeked *step
+}
+
+// coroutine is the part of a generator shared with the goroutine running
+// its body.
+type coroutine struct {
+	function Function
+	args     []interface{}
+	interp   *Interpreter
+	started  bool
+	done     bool
+	resume   chan struct{}
+	steps    chan step
+	// closed is closed to stop the body at its next yield.
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// step is what the body of a generator produces when it pauses or ends.
+type step struct {
+	value interface{}
+	err   error
+	done  bool
+}
+
+// errClosed unwinds the body of a closed generator from its pending yield.
+type errClosed struct{}
+
+func (errClosed) Error() string {
+	return "generator is closed"
+}
+
+func newGenerator(i *Interpreter, f Function, args []interface{}) *Generator {
+	c := &coroutine{
+		function: f,
+		args:     args,
+		resume:   make(chan struct{}),
+		steps:    make(chan step),
+		closed:   make(chan struct{}),
+	}
+
+	c.interp = i.fork()
+	c.interp.generator = c
+
+	g := &Generator{coroutine: c}
+	runtime.SetFinalizer(g, (*Generator).Close)
+
+	return g
+}
+
+func (g *Generator) String() string {
+	return "<generator " + g.function.declaration.Name.Lexeme() + ">"
+}
+
+// Next returns the next value of the generator, or false when the
+// function has returned.
+func (g *Generator) Next() (interface{}, bool, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	s := g.advance()
+
+	return s.value, !s.done, s.err
+}
+
+// HasNext reports whether the generator has another value, running its
+// body up to the next yield.
+func (g *Generator) HasNext() (bool, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.peeked == nil {
+		s := g.advance()
+		g.peeked = &s
+	}
+
+	return !g.peeked.done, g.peeked.err
+}
+
+// Close stops the body of the generator at its pending yield, after which
+// the generator has no more values. It is called when a for-in loop over
+// the generator exits early, and when the generator is garbage collected.
+func (g *Generator) Close() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.peeked = nil
+	g.done = true
+	g.closeOnce.Do(func() { close(g.closed) })
+}
+
+func (g *Generator) advance() step {
+	if g.peeked != nil {
+		s := *g.peeked
+		g.peeked = nil
+		return s
+	}
+
+	if g.done {
+		return step{done: true}
+	}
+
+	if !g.started {
+		g.started = true
+		go g.run()
+	} else {
+		g.resume <- struct{}{}
+	}
+
+	s := <-g.steps
+	g.done = s.done
+
+	return s
+}
+
+func (c *coroutine) run() {
+	_, err := c.function.run(c.interp, c.args)
+	if _, ok := err.(errClosed); ok {
+		return
+	}
+
+	select {
+	case c.steps <- step{err: err, done: true}:
+	case <-c.closed:
+	}
+}
+
+// yield is called from the body of the generator: it hands value to the
+// caller of advance and waits to be resumed, or fails with errClosed once
+// the generator is closed.
+func (c *coroutine) yield(value interface{}) error {
+	select {
+	case c.steps <- step{value: value}:
+	case <-c.closed:
+		return errClosed{}
+	}
+
+	select {
+	case <-c.resume:
+		return nil
+	case <-c.closed:
+		return errClosed{}
+	}
+}
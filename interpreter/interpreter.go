@@ -6,8 +6,12 @@ import (
 	"glox/environement"
 	"glox/errors"
 	"glox/token"
+	"math"
+	"os"
 	"reflect"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -15,33 +19,163 @@ type Interpreter struct {
 	env *environement.Env
     globalEnv *environement.Env
     locals map[ast.Expr]int
+    tailCallSites map[*ast.Call]bool
+    generators map[*ast.Function]bool
+    // resolution guards locals, tailCallSites and generators, which are
+    // shared with spawned tasks while the REPL resolves new input.
+    resolution *sync.RWMutex
+    // tasks counts the spawned tasks that are still running.
+    tasks *sync.WaitGroup
+    // generator is set when running the body of a generator.
+    generator *coroutine
+    tailCalls bool
+    echo bool
+    // fsRoots are the directories the file system natives may access.
+    fsRoots []string
+    exited bool
+    exitCode int
+    clock Clock
+    // started is the time the timer native counts from.
+    started time.Time
+    // random is shared with spawned tasks, so seed affects all of them.
+    random *random
 }
 
 func NewInterpreter() Interpreter {
     env := environement.NewEnvironement(nil)
-    env.Define("clock", NativeFunction{
-        arity: 0,
-        call: func(_ *Interpreter, _ []interface{}) (interface{}, error) {
-            return float64(time.Now().UnixMilli()) / 1000, nil
-        },
-    })
-
-    return Interpreter{env: env, globalEnv: env, locals: map[ast.Expr]int{}}
+    defineNatives(env, timeNatives)
+    defineNatives(env, randomNatives)
+    defineNatives(env, stringNatives)
+    defineNatives(env, mathNatives)
+    defineNatives(env, fsNatives)
+    defineNatives(env, collectionNatives)
+    defineNatives(env, jsonNatives)
+    defineNatives(env, processNatives)
+    defineNatives(env, regexNatives)
+    defineNatives(env, concurrencyNatives)
+    env.Define("args", NewList([]interface{}{}))
+    env.Define("PI", math.Pi)
+    env.Define("E", math.E)
+
+    return Interpreter{
+        env: env,
+        globalEnv: env,
+        locals: map[ast.Expr]int{},
+        tailCallSites: map[*ast.Call]bool{},
+        generators: map[*ast.Function]bool{},
+        resolution: &sync.RWMutex{},
+        tasks: &sync.WaitGroup{},
+        tailCalls: true,
+        clock: systemClock{},
+        started: time.Now(),
+        random: newRandom(time.Now().UnixNano()),
+    }
 }
 
 func (i *Interpreter) Interpret(statements []ast.Stmt) {
 	for _, s := range statements {
-		if err := i.execute(s); err != nil {
-			errors.RuntimeError(err)
+		if err := i.interpretStmt(s); err != nil {
+			if exit, ok := err.(Exit); ok {
+				i.exited, i.exitCode = true, exit.Code
+			} else {
+				errors.RuntimeError(err)
+			}
 			break
 		}
 	}
 }
 
+func (i *Interpreter) interpretStmt(s ast.Stmt) error {
+	e, ok := s.(*ast.Expression)
+
+	if !i.echo || !ok {
+		return i.execute(s)
+	}
+
+	val, err := i.evaluate(e.Exp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(Stringify(val))
+
+	return nil
+}
+
+// Evaluate evaluates a standalone expression in the current environment.
+func (i *Interpreter) Evaluate(expr ast.Expr) (interface{}, error) {
+	return i.evaluate(expr)
+}
+
+// SetEcho makes Interpret print the value of top-level expression statements.
+func (i *Interpreter) SetEcho(echo bool) {
+    i.echo = echo
+}
+
+// Globals returns the sorted names bound in the global environment.
+func (i *Interpreter) Globals() []string {
+    return i.globalEnv.Names()
+}
+
+// Global returns the value bound to name in the global environment.
+func (i *Interpreter) Global(name string) interface{} {
+    val, _ := i.globalEnv.GetAt(0, name)
+
+    return val
+}
+
 func (i *Interpreter) Resolve(e ast.Expr, depth int) {
+    i.resolution.Lock()
+    defer i.resolution.Unlock()
+
     i.locals[e] = depth
 }
 
+func (i *Interpreter) depth(e ast.Expr) (int, bool) {
+    i.resolution.RLock()
+    defer i.resolution.RUnlock()
+
+    distance, ok := i.locals[e]
+    return distance, ok
+}
+
+// MarkTailCall records a call whose value is directly returned by a function.
+func (i *Interpreter) MarkTailCall(call *ast.Call) {
+    i.resolution.Lock()
+    defer i.resolution.Unlock()
+
+    i.tailCallSites[call] = true
+}
+
+func (i *Interpreter) isTailCall(call *ast.Call) bool {
+    i.resolution.RLock()
+    defer i.resolution.RUnlock()
+
+    return i.tailCalls && i.tailCallSites[call]
+}
+
+// MarkGenerator records a function whose body contains a yield.
+func (i *Interpreter) MarkGenerator(fn *ast.Function) {
+    i.resolution.Lock()
+    defer i.resolution.Unlock()
+
+    i.generators[fn] = true
+}
+
+// IsGenerator reports whether calling fn returns a Generator.
+func (i *Interpreter) IsGenerator(fn *ast.Function) bool {
+    i.resolution.RLock()
+    defer i.resolution.RUnlock()
+
+    return i.generators[fn]
+}
+
+// SetTailCalls toggles running marked tail calls in constant stack space.
+// Disabling it keeps a Go stack frame per Lox call, which helps debugging.
+func (i *Interpreter) SetTailCalls(enabled bool) {
+    i.tailCalls = enabled
+}
+
 func (i *Interpreter) execute(s ast.Stmt) error {
 	return s.Accept(i)
 }
@@ -58,6 +192,21 @@ func (i *Interpreter) VisitGroupingExpr(expr *ast.Grouping) (interface{}, error)
 	return i.evaluate(expr.Expression)
 }
 
+func (i *Interpreter) VisitInterpolationExpr(expr *ast.Interpolation) (interface{}, error) {
+	var b strings.Builder
+
+	for _, part := range expr.Parts {
+		val, err := i.evaluate(part)
+		if err != nil {
+			return nil, err
+		}
+
+		b.WriteString(Stringify(val))
+	}
+
+	return b.String(), nil
+}
+
 func (i *Interpreter) VisitUnaryExpr(expr *ast.Unary) (interface{}, error) {
 	right, err := i.evaluate(expr.Right)
 	if err != nil {
@@ -66,7 +215,7 @@ func (i *Interpreter) VisitUnaryExpr(expr *ast.Unary) (interface{}, error) {
 
 	switch expr.Operator.Type() {
 	case token.BANG:
-		return !isTruthy(right), nil
+		return !IsTruthy(right), nil
 
 	case token.MINUS:
 		if err := checkNumberOperands(expr.Operator, right); err != nil {
@@ -74,6 +223,14 @@ func (i *Interpreter) VisitUnaryExpr(expr *ast.Unary) (interface{}, error) {
 		}
 
 		return -right.(float64), nil
+
+	case token.TILDE:
+		operands, err := integerOperands(expr.Operator, right)
+		if err != nil {
+			return nil, err
+		}
+
+		return float64(^operands[0]), nil
 	}
 
 	return nil, nil
@@ -108,6 +265,19 @@ func (i *Interpreter) VisitBinaryExpr(expr *ast.Binary) (interface{}, error) {
 	case token.STAR:
 		return left.(float64) * right.(float64), nil
 
+	case token.PERCENT:
+		if right.(float64) == 0 {
+			return nil, errors.NewRuntimeErr(expr.Operator, "Divisor must be different from 0")
+		}
+
+		return math.Mod(left.(float64), right.(float64)), nil
+
+	case token.STAR_STAR:
+		return math.Pow(left.(float64), right.(float64)), nil
+
+	case token.AMPERSAND, token.PIPE, token.CARET, token.LESS_LESS, token.GREATER_GREATER:
+		return bitwise(expr.Operator, left, right)
+
 	case token.PLUS:
 		lF, lFOk := left.(float64)
 		rF, rFOk := right.(float64)
@@ -120,7 +290,7 @@ func (i *Interpreter) VisitBinaryExpr(expr *ast.Binary) (interface{}, error) {
 		_, rSOk := right.(string)
 
 		if lSOk || rSOk {
-			return stringify(left) + stringify(right), nil
+			return Stringify(left) + Stringify(right), nil
 		}
 
 	case token.GREATER:
@@ -146,33 +316,92 @@ func (i *Interpreter) VisitBinaryExpr(expr *ast.Binary) (interface{}, error) {
 }
 
 func (i *Interpreter) VisitCallExpr(e *ast.Call) (interface{}, error) {
-    callee, err := i.evaluate(e.Callee)
+    function, args, err := i.prepareCall(e)
     if err != nil {
         return nil, err
     }
 
+    return i.call(function, args, e.Paren)
+}
+
+func (i *Interpreter) call(function Callable, args []interface{}, paren token.Token) (interface{}, error) {
+    val, err := function.Call(i, args)
+
+    if e, ok := err.(nativeError); ok {
+        return nil, errors.NewRuntimeErr(paren, e.message)
+    }
+
+    return val, err
+}
+
+// prepareCall evaluates the callee and the arguments of a call and checks
+// that they can be used together, without performing the call itself.
+func (i *Interpreter) prepareCall(e *ast.Call) (Callable, []interface{}, error) {
+    callee, err := i.evaluate(e.Callee)
+    if err != nil {
+        return nil, nil, err
+    }
+
     args := []interface{}{}
 
     for _, arg := range e.Arguments {
         val, err := i.evaluate(arg)
         if err != nil {
-            return nil, err
+            return nil, nil, err
         }
 
         args = append(args, val)
     }
+
+    named := []interface{}{}
+
+    for _, arg := range e.NamedArguments {
+        val, err := i.evaluate(arg.Value)
+        if err != nil {
+            return nil, nil, err
+        }
+
+        named = append(named, val)
+    }
     
     function, ok := callee.(Callable)
 
     if !ok {
-        return nil, errors.NewRuntimeErr(e.Paren, "Can only call functions and classes.")
+        return nil, nil, errors.NewRuntimeErr(e.Paren, "Can only call functions and classes.")
     }
 
-    if len(args) != function.Arity() {
-        return nil, errors.NewRuntimeErr(e.Paren, fmt.Sprintf("Expected %v arguments but got %v.", function.Arity(), len(args)))
+    args, err = i.bind(function, e, args, named)
+    if err != nil {
+        return nil, nil, err
     }
     
-    return function.Call(i, args)
+    return function, args, nil
+}
+
+func (i *Interpreter) VisitCoalesceExpr(e *ast.Coalesce) (interface{}, error) {
+	left, err := i.evaluate(e.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	if left != nil {
+		return left, nil
+	}
+
+	return i.evaluate(e.Right)
+}
+
+func (i *Interpreter) VisitConditionalExpr(e *ast.Conditional) (interface{}, error) {
+	c, err := i.evaluate(e.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	if IsTruthy(c) {
+		return i.evaluate(e.ThenBranch)
+	}
+
+	return i.evaluate(e.ElseBranch)
 }
 
 func (i *Interpreter) VisitLogicalExpr(e *ast.Logical) (interface{}, error) {
@@ -182,11 +411,11 @@ func (i *Interpreter) VisitLogicalExpr(e *ast.Logical) (interface{}, error) {
 	}
 
 	if e.Operator.Type() == token.OR {
-		if isTruthy(left) {
+		if IsTruthy(left) {
 			return left, nil
 		}
 	} else {
-		if !isTruthy(left) {
+		if !IsTruthy(left) {
 			return left, nil
 		}
 	}
@@ -211,19 +440,64 @@ func (i *Interpreter) VisitAssignExpr(expr *ast.Assign) (interface{}, error) {
 		return nil, err
 	}
 
-    if distance, ok := i.locals[expr]; ok {
-        i.env.AssignAt(distance, expr.Name, val)
-    } else {
-        i.globalEnv.Assign(expr.Name, val)
-    }
+	if err := i.assignVariable(expr.Name, expr, val); err != nil {
+		return nil, err
+	}
 
 	return val, nil
 }
 
+func (i *Interpreter) VisitIncrementExpr(expr *ast.Increment) (interface{}, error) {
+	val, err := i.lookUpVariable(expr.Name, expr)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkNumberOperands(expr.Operator, val); err != nil {
+		return nil, err
+	}
+
+	old := val.(float64)
+	updated := old + 1
+
+	if expr.Operator.Type() == token.MINUS_MINUS {
+		updated = old - 1
+	}
+
+	if err := i.assignVariable(expr.Name, expr, updated); err != nil {
+		return nil, err
+	}
+
+	if expr.Prefix {
+		return updated, nil
+	}
+
+	return old, nil
+}
+
+func (i *Interpreter) assignVariable(name token.Token, expr ast.Expr, val interface{}) error {
+    if distance, ok := i.depth(expr); ok {
+        return i.env.AssignAt(distance, name, val)
+    }
+
+    return i.globalEnv.Assign(name, val)
+}
+
 func (i *Interpreter) VisitBlockStmt(s *ast.Block) error {
 	return i.executeBlock(s.Statements, environement.NewEnvironement(i.env))
 }
 
+// evaluateIn evaluates e in env, such as the default value of a parameter
+// in the environment of the function's call.
+func (i *Interpreter) evaluateIn(e ast.Expr, env *environement.Env) (interface{}, error) {
+	prev := i.env
+	defer func() { i.env = prev }()
+
+	i.env = env
+
+	return i.evaluate(e)
+}
+
 func (i *Interpreter) executeBlock(statements []ast.Stmt, env *environement.Env) error {
 	prev := i.env
 
@@ -252,8 +526,24 @@ func (i *Interpreter) VisitExpressionStmt(s *ast.Expression) error {
 	return err
 }
 
+// VisitForInStmt runs the body with a fresh binding of the loop variable
+// for every element, so that closures capture each value separately.
+func (i *Interpreter) VisitForInStmt(s *ast.ForIn) error {
+    iterable, err := i.evaluate(s.Iterable)
+    if err != nil {
+        return err
+    }
+
+    return i.iterate(iterable, s.Keyword, func(value interface{}) error {
+        env := environement.NewEnvironement(i.env)
+        env.Define(s.Name.Lexeme(), value)
+
+        return i.executeBlock([]ast.Stmt{s.Body}, env)
+    })
+}
+
 func (i *Interpreter) VisitFunctionStmt(s *ast.Function) error {
-    fn := NewFunction(*s, i.env)
+    fn := NewFunction(s, i.env)
     i.env.Define(s.Name.Lexeme(), fn)
 
     return nil
@@ -266,15 +556,29 @@ func (i *Interpreter) VisitPrintStmt(s *ast.Print) error {
 		return err
 	}
 
-	fmt.Println(stringify(val))
+	fmt.Println(Stringify(val))
 
 	return nil
 }
 
 func (i *Interpreter) VisitReturnStmt(s *ast.Return) error {
     var value interface{}
-    
-    if s.Value != nil {
+
+    if call, ok := s.Value.(*ast.Call); ok && i.isTailCall(call) {
+        function, args, err := i.prepareCall(call)
+        if err != nil {
+            return err
+        }
+
+        if fn, ok := function.(Function); ok && !i.IsGenerator(fn.declaration) {
+            return TailCall{fn, args}
+        }
+
+        value, err = i.call(function, args, call.Paren)
+        if err != nil {
+            return err
+        }
+    } else if s.Value != nil {
         val, err := i.evaluate(s.Value)
         if err != nil {
             return err
@@ -286,6 +590,112 @@ func (i *Interpreter) VisitReturnStmt(s *ast.Return) error {
     return Return{value}
 }
 
+// fork returns an interpreter for a spawned task. It shares the globals and
+// the resolution of the program, but has its own current environment.
+func (i *Interpreter) fork() *Interpreter {
+	task := *i
+	task.env = i.globalEnv
+	task.generator = nil
+
+	return &task
+}
+
+// VisitSpawnStmt runs the call on a new goroutine. The main goroutine may be
+// blocked on a channel or a wait group, so an exit in a task can't be handed
+// to it: the task exits the process directly instead, which is an abrupt
+// exit that skips whatever the main goroutine would have reported. Output
+// is written unbuffered, so nothing already printed is lost.
+func (i *Interpreter) VisitSpawnStmt(s *ast.Spawn) error {
+	function, args, err := i.prepareCall(s.Call)
+	if err != nil {
+		return err
+	}
+
+	task := i.fork()
+	i.tasks.Add(1)
+
+	go func() {
+		defer i.tasks.Done()
+
+		_, err := task.call(function, args, s.Call.Paren)
+
+		if exit, ok := err.(Exit); ok {
+			os.Exit(exit.Code)
+		}
+
+		if err != nil {
+			errors.RuntimeError(err)
+		}
+	}()
+
+	return nil
+}
+
+// WaitTasks waits until every spawned task has finished.
+func (i *Interpreter) WaitTasks() {
+	i.tasks.Wait()
+}
+
+func (i *Interpreter) VisitSelectStmt(s *ast.Select) error {
+	cases := make([]reflect.SelectCase, len(s.Cases))
+
+	for j, c := range s.Cases {
+		val, err := i.evaluate(c.Channel)
+		if err != nil {
+			return err
+		}
+
+		channel, ok := val.(*Channel)
+		if !ok {
+			return errors.NewRuntimeErr(c.Operation, "Operand must be a channel.")
+		}
+
+		cases[j] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(channel.ch)}
+
+		if c.Value != nil {
+			val, err := i.evaluate(c.Value)
+			if err != nil {
+				return err
+			}
+
+			cases[j].Dir = reflect.SelectSend
+			cases[j].Send = reflect.ValueOf(&val).Elem()
+		}
+	}
+
+	if s.Default != nil {
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectDefault})
+	}
+
+	var chosen int
+	var received reflect.Value
+
+	if err := guardClosed(func() { chosen, received, _ = reflect.Select(cases) }); err != nil {
+		return errors.NewRuntimeErr(s.Keyword, err.Error())
+	}
+
+	if chosen == len(s.Cases) {
+		return i.execute(s.Default)
+	}
+
+	c := s.Cases[chosen]
+
+	if c.Name == nil {
+		return i.execute(c.Body)
+	}
+
+	env := environement.NewEnvironement(i.env)
+
+	var value interface{}
+	if received.IsValid() && !received.IsNil() {
+		value = received.Interface()
+	}
+
+	env.Define(c.Name.Lexeme(), value)
+
+	return i.executeBlock([]ast.Stmt{c.Body}, env)
+}
+
 func (i *Interpreter) VisitVarStmt(s *ast.Var) error {
 	var value interface{}
 
@@ -299,6 +709,11 @@ func (i *Interpreter) VisitVarStmt(s *ast.Var) error {
 		value = val
 	}
 
+	if s.Constant {
+		i.env.DefineConstant(s.Name.Lexeme(), value)
+		return nil
+	}
+
 	i.env.Define(s.Name.Lexeme(), value)
 
 	return nil
@@ -310,7 +725,7 @@ func (i *Interpreter) VisitIfStmt(s *ast.If) error {
 		return err
 	}
 
-	if isTruthy(c) {
+	if IsTruthy(c) {
 		return i.execute(s.ThenBranch)
 	} else if s.ElseBranch != nil {
 		return i.execute(s.ElseBranch)
@@ -319,6 +734,25 @@ func (i *Interpreter) VisitIfStmt(s *ast.If) error {
 	return nil
 }
 
+func (i *Interpreter) VisitYieldStmt(s *ast.Yield) error {
+    var value interface{}
+
+    if s.Value != nil {
+        val, err := i.evaluate(s.Value)
+        if err != nil {
+            return err
+        }
+
+        value = val
+    }
+
+    if i.generator == nil {
+        return errors.NewRuntimeErr(s.Keyword, "Can't yield outside of a generator.")
+    }
+
+    return i.generator.yield(value)
+}
+
 func (i *Interpreter) VisitWhileStmt(s *ast.While) error {
     for {
         val, err := i.evaluate(s.Condition)
@@ -327,19 +761,20 @@ func (i *Interpreter) VisitWhileStmt(s *ast.While) error {
             return err
         }
 
-        if !isTruthy(val) {
+        if !IsTruthy(val) {
             break
         }
 
-        i.execute(s.Body)
-
+        if err := i.execute(s.Body); err != nil {
+            return err
+        }
     }
 
     return nil
 }
 
 func (i *Interpreter) lookUpVariable(name token.Token, expr ast.Expr) (interface{}, error) {
-    if distance, ok := i.locals[expr]; ok {
+    if distance, ok := i.depth(expr); ok {
         return i.env.GetAt(distance, name.Lexeme())
     } else {
         return i.globalEnv.Get(name)
@@ -348,7 +783,7 @@ func (i *Interpreter) lookUpVariable(name token.Token, expr ast.Expr) (interface
 
 func checkNumberOperands(operator token.Token, operands ...interface{}) error {
 	switch operator.Type() {
-	case token.MINUS, token.SLASH, token.STAR:
+	case token.MINUS, token.SLASH, token.STAR, token.PERCENT, token.PLUS_PLUS, token.MINUS_MINUS, token.STAR_STAR, token.GREATER, token.GREATER_EQUAL, token.LESS, token.LESS_EQUAL:
 		for _, o := range operands {
 			if _, ok := o.(float64); !ok {
 				plural := ""
@@ -364,7 +799,59 @@ func checkNumberOperands(operator token.Token, operands ...interface{}) error {
 	return nil
 }
 
-func isTruthy(obj interface{}) bool {
+// maxInteger is the largest integer that a float64 holds exactly.
+const maxInteger = 1 << 53
+
+func integerOperands(operator token.Token, operands ...interface{}) ([]int64, error) {
+	integers := make([]int64, len(operands))
+
+	for j, o := range operands {
+		f, ok := o.(float64)
+
+		if !ok || f != math.Trunc(f) || math.Abs(f) > maxInteger {
+			plural := ""
+			if len(operands) > 1 {
+				plural = "s"
+			}
+
+			return nil, errors.NewRuntimeErr(operator, fmt.Sprintf("Operand%v must be integer%v.", plural, plural))
+		}
+
+		integers[j] = int64(f)
+	}
+
+	return integers, nil
+}
+
+func bitwise(operator token.Token, left, right interface{}) (interface{}, error) {
+	operands, err := integerOperands(operator, left, right)
+	if err != nil {
+		return nil, err
+	}
+
+	l, r := operands[0], operands[1]
+
+	switch operator.Type() {
+	case token.AMPERSAND:
+		return float64(l & r), nil
+	case token.PIPE:
+		return float64(l | r), nil
+	case token.CARET:
+		return float64(l ^ r), nil
+	}
+
+	if r < 0 || r > 63 {
+		return nil, errors.NewRuntimeErr(operator, "Shift count must be between 0 and 63.")
+	}
+
+	if operator.Type() == token.LESS_LESS {
+		return float64(l << r), nil
+	}
+
+	return float64(l >> r), nil
+}
+
+func IsTruthy(obj interface{}) bool {
 	if obj == nil {
 		return false
 	}
@@ -380,20 +867,33 @@ func isEqual(a, b interface{}) bool {
 	return reflect.DeepEqual(a, b)
 }
 
-func stringify(obj interface{}) string {
+func Stringify(obj interface{}) string {
 	if obj == nil {
 		return "nil"
 	}
 
-	if _, ok := obj.(float64); ok {
-		text := fmt.Sprintf("%f", obj)
+	if f, ok := obj.(float64); ok {
+		return formatNumber(f)
+	}
 
-		if strings.HasSuffix(text, ".000000") {
-			text = text[:len(text)-7]
-		}
+	return fmt.Sprintf("%v", obj)
+}
 
-		return text
+// formatNumber prints the shortest representation that reads back as the
+// same number, switching to exponent notation for very large or small ones.
+func formatNumber(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "Infinity"
+	case math.IsInf(f, -1):
+		return "-Infinity"
 	}
 
-	return fmt.Sprintf("%v", obj)
+	if abs := math.Abs(f); abs != 0 && (abs >= 1e21 || abs < 1e-6) {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+
+	return strconv.FormatFloat(f, 'f', -1, 64)
 }
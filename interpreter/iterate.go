@@ -0,0 +1,66 @@
+package interpreter
+
+import (
+	"glox/errors"
+	"glox/token"
+)
+
+// iterate calls body with every element of a list, every key of a map,
+// every character of a string, every number of a range or every value of a
+// generator, stopping at the first error.
+func (i *Interpreter) iterate(iterable interface{}, keyword token.Token, body func(interface{}) error) error {
+	switch it := iterable.(type) {
+	case *List:
+		// The list is read at each step, so the body can append to it.
+		for j := 0; j < it.Len(); j++ {
+			if err := body(it.at(j)); err != nil {
+				return err
+			}
+		}
+
+	case *Map:
+		for _, k := range it.Keys() {
+			if err := body(k); err != nil {
+				return err
+			}
+		}
+
+	case string:
+		for _, r := range it {
+			if err := body(string(r)); err != nil {
+				return err
+			}
+		}
+
+	case *Range:
+		for n := it.start; it.contains(n); n += it.step {
+			if err := body(n); err != nil {
+				return err
+			}
+		}
+
+	case *Generator:
+		// A loop that exits early closes the generator, which would
+		// otherwise keep its goroutine waiting on the next value.
+		for {
+			value, ok, err := it.Next()
+			if err != nil {
+				return err
+			}
+
+			if !ok {
+				return nil
+			}
+
+			if err := body(value); err != nil {
+				it.Close()
+				return err
+			}
+		}
+
+	default:
+		return errors.NewRuntimeErr(keyword, "Can only iterate over lists, maps, strings, ranges and generators.")
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package interpreter
+
+import "fmt"
+
+var collectionNatives = map[string]NativeFunction{
+	"newList": {arity: 0, call: func(_ *Interpreter, _ []interface{}) (interface{}, error) {
+		return NewList([]interface{}{}), nil
+	}},
+
+	"newMap": {arity: 0, call: func(_ *Interpreter, _ []interface{}) (interface{}, error) {
+		return NewMap(), nil
+	}},
+
+	"range": {arity: 2, optional: 1, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		bounds := [3]float64{0, 0, 1}
+
+		for j := range args {
+			n, err := numberArg("range", args, j)
+			if err != nil {
+				return nil, err
+			}
+
+			bounds[j] = n
+		}
+
+		if bounds[2] == 0 {
+			return nil, nativeError{"Range step must be different from 0."}
+		}
+
+		return &Range{start: bounds[0], end: bounds[1], step: bounds[2]}, nil
+	}},
+
+	"get": {arity: 2, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		switch c := args[0].(type) {
+		case *List:
+			i, err := indexArg("get", c, args, 1)
+			if err != nil {
+				return nil, err
+			}
+
+			return c.at(i), nil
+
+		case *Map:
+			key, err := stringArg("get", args, 1)
+			if err != nil {
+				return nil, err
+			}
+
+			v, _ := c.Get(key)
+			return v, nil
+		}
+
+		return nil, nativeError{"Argument 1 of 'get' must be a list or a map."}
+	}},
+
+	"set": {arity: 3, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		switch c := args[0].(type) {
+		case *List:
+			i, err := indexArg("set", c, args, 1)
+			if err != nil {
+				return nil, err
+			}
+
+			return nil, c.setAt(i, args[2])
+
+		case *Map:
+			key, err := stringArg("set", args, 1)
+			if err != nil {
+				return nil, err
+			}
+
+			return nil, c.put(key, args[2])
+		}
+
+		return nil, nativeError{"Argument 1 of 'set' must be a list or a map."}
+	}},
+
+	"has": {arity: 2, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		m, err := mapArg("has", args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := stringArg("has", args, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		_, ok := m.Get(key)
+		return ok, nil
+	}},
+
+	"keys": {arity: 1, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		m, err := mapArg("keys", args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		return newStringList(m.Keys()), nil
+	}},
+
+	"next": {arity: 1, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		g, err := generatorArg("next", args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		value, _, err := g.Next()
+		return value, err
+	}},
+
+	"hasNext": {arity: 1, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		g, err := generatorArg("hasNext", args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		return g.HasNext()
+	}},
+
+	// freeze makes a list or a map read-only and returns it. Other values
+	// are already immutable and are returned as is.
+	"freeze": {arity: 1, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		switch c := args[0].(type) {
+		case *List:
+			c.freeze()
+		case *Map:
+			c.freeze()
+		}
+
+		return args[0], nil
+	}},
+
+	"push": {arity: 2, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		l, ok := args[0].(*List)
+		if !ok {
+			return nil, nativeError{"Argument 1 of 'push' must be a list."}
+		}
+
+		return nil, l.push(args[1])
+	}},
+}
+
+func mapArg(name string, args []interface{}, i int) (*Map, error) {
+	if m, ok := args[i].(*Map); ok {
+		return m, nil
+	}
+
+	return nil, nativeError{fmt.Sprintf("Argument %v of '%v' must be a map.", i+1, name)}
+}
+
+func generatorArg(name string, args []interface{}, i int) (*Generator, error) {
+	if g, ok := args[i].(*Generator); ok {
+		return g, nil
+	}
+
+	return nil, nativeError{fmt.Sprintf("Argument %v of '%v' must be a generator.", i+1, name)}
+}
+
+func indexArg(name string, l *List, args []interface{}, i int) (int, error) {
+	index, err := intArg(name, args, i)
+	if err != nil {
+		return 0, err
+	}
+
+	if length := l.Len(); index < 0 || index >= length {
+		return 0, nativeError{fmt.Sprintf("Index %v out of range for length %v.", index, length)}
+	}
+
+	return index, nil
+}
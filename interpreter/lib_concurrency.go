@@ -0,0 +1,118 @@
+package interpreter
+
+import "fmt"
+
+var concurrencyNatives = map[string]NativeFunction{
+	"channel": {arity: 1, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		capacity, err := intArg("channel", args, 0)
+		if err != nil || capacity < 0 {
+			return nil, nativeError{"Channel capacity must be a non-negative integer."}
+		}
+
+		return &Channel{ch: make(chan interface{}, capacity)}, nil
+	}},
+
+	"send": {arity: 2, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		c, err := channelArg("send", args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, guardClosed(func() { c.ch <- args[1] })
+	}},
+
+	"receive": {arity: 1, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		c, err := channelArg("receive", args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		return <-c.ch, nil
+	}},
+
+	"close": {arity: 1, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		c, err := channelArg("close", args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, guardClosed(func() { close(c.ch) })
+	}},
+
+	"waitGroup": {arity: 0, call: func(_ *Interpreter, _ []interface{}) (interface{}, error) {
+		return &WaitGroup{}, nil
+	}},
+
+	"wgAdd": {arity: 2, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		w, err := waitGroupArg("wgAdd", args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		n, err := intArg("wgAdd", args, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, guardNegative(func() { w.wg.Add(n) })
+	}},
+
+	"wgDone": {arity: 1, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		w, err := waitGroupArg("wgDone", args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, guardNegative(w.wg.Done)
+	}},
+
+	"wgWait": {arity: 1, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		w, err := waitGroupArg("wgWait", args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		w.wg.Wait()
+		return nil, nil
+	}},
+}
+
+func channelArg(name string, args []interface{}, i int) (*Channel, error) {
+	if c, ok := args[i].(*Channel); ok {
+		return c, nil
+	}
+
+	return nil, nativeError{fmt.Sprintf("Argument %v of '%v' must be a channel.", i+1, name)}
+}
+
+func waitGroupArg(name string, args []interface{}, i int) (*WaitGroup, error) {
+	if w, ok := args[i].(*WaitGroup); ok {
+		return w, nil
+	}
+
+	return nil, nativeError{fmt.Sprintf("Argument %v of '%v' must be a wait group.", i+1, name)}
+}
+
+// guardClosed runs a send or a close, turning the panic caused by a closed
+// channel into an error.
+func guardClosed(f func()) (err error) {
+	defer func() {
+		if recover() != nil {
+			err = nativeError{"Channel is closed."}
+		}
+	}()
+
+	f()
+	return nil
+}
+
+func guardNegative(f func()) (err error) {
+	defer func() {
+		if recover() != nil {
+			err = nativeError{"Wait group counter cannot be negative."}
+		}
+	}()
+
+	f()
+	return nil
+}
@@ -0,0 +1,189 @@
+package interpreter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// fsNatives access the file system. Every path must lie inside one of the
+// roots given to AllowFS, otherwise the call fails.
+var fsNatives = map[string]NativeFunction{
+	"readFile": {arity: 1, call: func(i *Interpreter, args []interface{}) (interface{}, error) {
+		path, err := i.pathArg("readFile", args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fsError(err)
+		}
+
+		return string(b), nil
+	}},
+
+	"writeFile": {arity: 2, call: func(i *Interpreter, args []interface{}) (interface{}, error) {
+		return nil, i.writeFile("writeFile", args, os.O_TRUNC)
+	}},
+
+	"appendFile": {arity: 2, call: func(i *Interpreter, args []interface{}) (interface{}, error) {
+		return nil, i.writeFile("appendFile", args, os.O_APPEND)
+	}},
+
+	"listDir": {arity: 1, call: func(i *Interpreter, args []interface{}) (interface{}, error) {
+		path, err := i.pathArg("listDir", args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fsError(err)
+		}
+
+		names := make([]string, len(entries))
+		for j, e := range entries {
+			names[j] = e.Name()
+		}
+		sort.Strings(names)
+
+		return newStringList(names), nil
+	}},
+
+	"exists": {arity: 1, call: func(i *Interpreter, args []interface{}) (interface{}, error) {
+		path, err := i.pathArg("exists", args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = os.Stat(path)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fsError(err)
+		}
+
+		return err == nil, nil
+	}},
+
+	"removeFile": {arity: 1, call: func(i *Interpreter, args []interface{}) (interface{}, error) {
+		path, err := i.pathArg("removeFile", args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			return nil, nativeError{fmt.Sprintf("Cannot remove '%v': it is a directory.", args[0])}
+		}
+
+		if err := os.Remove(path); err != nil {
+			return nil, fsError(err)
+		}
+
+		return nil, nil
+	}},
+}
+
+// AllowFS lets the file system natives access the given directories and
+// everything below them. Without any root, every access is denied.
+func (i *Interpreter) AllowFS(roots ...string) error {
+	for _, root := range roots {
+		path, err := resolvePath(root)
+		if err != nil {
+			return err
+		}
+
+		i.fsRoots = append(i.fsRoots, path)
+	}
+
+	return nil
+}
+
+func (i *Interpreter) writeFile(name string, args []interface{}, flag int) error {
+	path, err := i.pathArg(name, args, 0)
+	if err != nil {
+		return err
+	}
+
+	content, err := stringArg(name, args, 1)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|flag, 0644)
+	if err != nil {
+		return fsError(err)
+	}
+
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		return fsError(err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fsError(err)
+	}
+
+	return nil
+}
+
+// pathArg returns the path passed as argument j, resolved to an absolute
+// path, after checking that it is inside an allowed root.
+func (i *Interpreter) pathArg(name string, args []interface{}, j int) (string, error) {
+	arg, err := stringArg(name, args, j)
+	if err != nil {
+		return "", err
+	}
+
+	path, err := resolvePath(arg)
+	if err != nil {
+		return "", fsError(err)
+	}
+
+	for _, root := range i.fsRoots {
+		if rel, err := filepath.Rel(root, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return path, nil
+		}
+	}
+
+	return "", nativeError{fmt.Sprintf("Access to '%v' is denied.", arg)}
+}
+
+// resolvePath makes path absolute and follows the symbolic links of its
+// existing part, so that links cannot escape an allowed root.
+func resolvePath(path string) (string, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	rest := ""
+	for {
+		resolved, err := filepath.EvalSymlinks(path)
+		if err == nil {
+			return filepath.Join(resolved, rest), nil
+		}
+
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+
+		parent := filepath.Dir(path)
+		if parent == path {
+			return filepath.Join(path, rest), nil
+		}
+
+		rest = filepath.Join(filepath.Base(path), rest)
+		path = parent
+	}
+}
+
+// fsError turns an error of the os package into a runtime error message.
+func fsError(err error) error {
+	if e, ok := err.(*os.PathError); ok {
+		return nativeError{fmt.Sprintf("Cannot %v '%v': %v.", e.Op, e.Path, e.Err)}
+	}
+
+	return nativeError{err.Error()}
+}
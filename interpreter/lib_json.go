@@ -0,0 +1,213 @@
+package interpreter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var jsonNatives = map[string]NativeFunction{
+	"jsonParse": {arity: 1, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		s, err := stringArg("jsonParse", args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		d := json.NewDecoder(strings.NewReader(s))
+		d.UseNumber()
+
+		value, err := decodeJSON(d)
+		if err == nil {
+			if _, err = d.Token(); err == io.EOF {
+				return value, nil
+			} else if err == nil {
+				err = fmt.Errorf("unexpected data after top-level value")
+			}
+		}
+
+		return nil, jsonError(d, err)
+	}},
+
+	"jsonStringify": {arity: 2, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		indent := ""
+
+		switch v := args[1].(type) {
+		case nil:
+		case string:
+			indent = v
+		default:
+			n, err := intArg("jsonStringify", args, 1)
+			if err != nil || n < 0 || n > 10 {
+				return nil, nativeError{"Argument 2 of 'jsonStringify' must be nil, a string or a number of spaces between 0 and 10."}
+			}
+
+			indent = strings.Repeat(" ", n)
+		}
+
+		var b bytes.Buffer
+		if err := encodeJSON(&b, args[0], map[interface{}]bool{}); err != nil {
+			return nil, err
+		}
+
+		if indent == "" {
+			return b.String(), nil
+		}
+
+		var out bytes.Buffer
+		json.Indent(&out, b.Bytes(), "", indent)
+
+		return out.String(), nil
+	}},
+}
+
+// decodeJSON reads the next JSON value of d, keeping the order of object
+// keys.
+func decodeJSON(d *json.Decoder) (interface{}, error) {
+	t, err := d.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := t.(type) {
+	case json.Delim:
+		if t == '[' {
+			elements := []interface{}{}
+
+			for d.More() {
+				e, err := decodeJSON(d)
+				if err != nil {
+					return nil, err
+				}
+
+				elements = append(elements, e)
+			}
+
+			_, err := d.Token()
+			return NewList(elements), err
+		}
+
+		m := NewMap()
+
+		for d.More() {
+			key, err := d.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := decodeJSON(d)
+			if err != nil {
+				return nil, err
+			}
+
+			m.Set(key.(string), value)
+		}
+
+		_, err := d.Token()
+		return m, err
+
+	case json.Number:
+		return strconv.ParseFloat(string(t), 64)
+	}
+
+	return t, nil
+}
+
+func jsonError(d *json.Decoder, err error) error {
+	offset := d.InputOffset()
+
+	if e, ok := err.(*json.SyntaxError); ok {
+		offset = e.Offset
+	}
+
+	message := strings.TrimPrefix(err.Error(), "json: ")
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		message = "unexpected end of input"
+	}
+
+	return nativeError{fmt.Sprintf("Invalid JSON at offset %v: %v.", offset, message)}
+}
+
+// encodeJSON writes the compact JSON form of value. seen holds the lists
+// and maps being encoded, to reject cyclic structures.
+func encodeJSON(b *bytes.Buffer, value interface{}, seen map[interface{}]bool) error {
+	switch v := value.(type) {
+	case nil:
+		b.WriteString("null")
+
+	case bool:
+		b.WriteString(strconv.FormatBool(v))
+
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nativeError{fmt.Sprintf("Cannot convert %v to JSON.", formatNumber(v))}
+		}
+
+		b.WriteString(formatNumber(v))
+
+	case string:
+		encodeJSONString(b, v)
+
+	case *List:
+		if seen[v] {
+			return nativeError{"Cannot convert a cyclic structure to JSON."}
+		}
+		seen[v] = true
+
+		b.WriteString("[")
+		for i, e := range v.Elements() {
+			if i > 0 {
+				b.WriteString(",")
+			}
+
+			if err := encodeJSON(b, e, seen); err != nil {
+				return err
+			}
+		}
+		b.WriteString("]")
+
+		delete(seen, v)
+
+	case *Map:
+		if seen[v] {
+			return nativeError{"Cannot convert a cyclic structure to JSON."}
+		}
+		seen[v] = true
+
+		b.WriteString("{")
+		for i, k := range v.Keys() {
+			if i > 0 {
+				b.WriteString(",")
+			}
+
+			encodeJSONString(b, k)
+			b.WriteString(":")
+
+			value, _ := v.Get(k)
+
+			if err := encodeJSON(b, value, seen); err != nil {
+				return err
+			}
+		}
+		b.WriteString("}")
+
+		delete(seen, v)
+
+	default:
+		return nativeError{fmt.Sprintf("Cannot convert %v to JSON.", Stringify(v))}
+	}
+
+	return nil
+}
+
+func encodeJSONString(b *bytes.Buffer, s string) {
+	e := json.NewEncoder(b)
+	e.SetEscapeHTML(false)
+	e.Encode(s)
+
+	// Encode terminates the value with a newline.
+	b.Truncate(b.Len() - 1)
+}
@@ -0,0 +1,112 @@
+package interpreter
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var mathNatives = map[string]NativeFunction{
+	"sqrt":  numberFunction("sqrt", math.Sqrt),
+	"abs":   numberFunction("abs", math.Abs),
+	"floor": numberFunction("floor", math.Floor),
+	"ceil":  numberFunction("ceil", math.Ceil),
+	"round": numberFunction("round", math.Round),
+	"sin":   numberFunction("sin", math.Sin),
+	"cos":   numberFunction("cos", math.Cos),
+	"tan":   numberFunction("tan", math.Tan),
+	"log":   numberFunction("log", math.Log),
+	"exp":   numberFunction("exp", math.Exp),
+
+	"pow": numberFunction2("pow", math.Pow),
+	"min": numberFunction2("min", math.Min),
+	"max": numberFunction2("max", math.Max),
+
+	"isNaN": {arity: 1, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		f, err := numberArg("isNaN", args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		return math.IsNaN(f), nil
+	}},
+
+	"isInfinite": {arity: 1, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		f, err := numberArg("isInfinite", args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		return math.IsInf(f, 0), nil
+	}},
+
+	"toFixed": {arity: 2, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		f, err := numberArg("toFixed", args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		digits, err := intArg("toFixed", args, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		if digits < 0 || digits > 100 {
+			return nil, nativeError{"Argument 2 of 'toFixed' must be between 0 and 100."}
+		}
+
+		return strconv.FormatFloat(f, 'f', digits, 64), nil
+	}},
+
+	"parseNumber": {arity: 1, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		s, err := stringArg("parseNumber", args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		s = strings.TrimSpace(s)
+		if !numberLiteral.MatchString(s) {
+			return nil, nil
+		}
+
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, nil
+		}
+
+		return f, nil
+	}},
+}
+
+// numberLiteral matches the number literals of the scanner, optionally
+// negated, so that parseNumber rejects forms such as "inf", "0x1p3" or
+// "1_000" that strconv accepts.
+var numberLiteral = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
+func numberFunction(name string, f func(float64) float64) NativeFunction {
+	return NativeFunction{arity: 1, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		x, err := numberArg(name, args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		return f(x), nil
+	}}
+}
+
+func numberFunction2(name string, f func(float64, float64) float64) NativeFunction {
+	return NativeFunction{arity: 2, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		x, err := numberArg(name, args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := numberArg(name, args, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		return f(x, y), nil
+	}}
+}
@@ -0,0 +1,56 @@
+package interpreter
+
+import (
+	"fmt"
+	"os"
+)
+
+// Exit is returned by the exit native to unwind the whole program.
+type Exit struct {
+	Code int
+}
+
+func (e Exit) Error() string {
+	return fmt.Sprintf("exit %v", e.Code)
+}
+
+var processNatives = map[string]NativeFunction{
+	"getenv": {arity: 1, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		name, err := stringArg("getenv", args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		if value, ok := os.LookupEnv(name); ok {
+			return value, nil
+		}
+
+		return nil, nil
+	}},
+
+	"exit": {arity: 1, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		code, err := intArg("exit", args, 0)
+		if err != nil || code < 0 || code > 255 {
+			return nil, nativeError{"Exit code must be an integer between 0 and 255."}
+		}
+
+		return nil, Exit{code}
+	}},
+
+	"stderr": {arity: 1, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		fmt.Fprintln(os.Stderr, Stringify(args[0]))
+
+		return nil, nil
+	}},
+}
+
+// SetArgs defines the args global, the list of the command-line arguments
+// given to the script.
+func (i *Interpreter) SetArgs(args []string) {
+	i.globalEnv.Define("args", newStringList(args))
+}
+
+// Exited reports whether the program called exit, and with which code.
+func (i *Interpreter) Exited() (int, bool) {
+	return i.exitCode, i.exited
+}
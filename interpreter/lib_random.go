@@ -0,0 +1,101 @@
+package interpreter
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+)
+
+var randomNatives = map[string]NativeFunction{
+	"random": {arity: 0, call: func(i *Interpreter, _ []interface{}) (interface{}, error) {
+		return i.random.float64(), nil
+	}},
+
+	"randomInt": {arity: 2, call: func(i *Interpreter, args []interface{}) (interface{}, error) {
+		lo, err := intArg("randomInt", args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		hi, err := intArg("randomInt", args, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		if hi < lo {
+			return nil, nativeError{fmt.Sprintf("Empty range [%v, %v].", lo, hi)}
+		}
+
+		return float64(lo + i.random.intN(hi-lo+1)), nil
+	}},
+
+	"shuffle": {arity: 1, call: func(i *Interpreter, args []interface{}) (interface{}, error) {
+		l, ok := args[0].(*List)
+		if !ok {
+			return nil, nativeError{"Argument of 'shuffle' must be a list."}
+		}
+
+		return nil, l.update(i.random.shuffle)
+	}},
+
+	"seed": {arity: 1, call: func(i *Interpreter, args []interface{}) (interface{}, error) {
+		n, err := numberArg("seed", args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		i.Seed(int64(n))
+		return nil, nil
+	}},
+}
+
+// Seed resets the random number generator, so that the random natives
+// return the same sequence for the same seed. The generator is shared with
+// spawned tasks, so seeding in a task also seeds the whole program.
+func (i *Interpreter) Seed(n int64) {
+	i.random.seed(n)
+}
+
+// random holds the generator of an interpreter and of its spawned tasks,
+// which use it concurrently.
+type random struct {
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+func newRandom(seed int64) *random {
+	r := &random{}
+	r.seed(seed)
+
+	return r
+}
+
+func (r *random) seed(seed int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rand = rand.New(rand.NewSource(seed))
+}
+
+func (r *random) float64() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.rand.Float64()
+}
+
+func (r *random) intN(n int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.rand.Intn(n)
+}
+
+func (r *random) shuffle(elements []interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rand.Shuffle(len(elements), func(a, b int) {
+		elements[a], elements[b] = elements[b], elements[a]
+	})
+}
@@ -0,0 +1,136 @@
+package interpreter
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"sync"
+)
+
+var regexNatives = map[string]NativeFunction{
+	"match": {arity: 2, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		re, s, err := regexArgs("match", args)
+		if err != nil {
+			return nil, err
+		}
+
+		return re.MatchString(s), nil
+	}},
+
+	"find": {arity: 2, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		re, s, err := regexArgs("find", args)
+		if err != nil {
+			return nil, err
+		}
+
+		loc := re.FindStringIndex(s)
+		if loc == nil {
+			return nil, nil
+		}
+
+		return s[loc[0]:loc[1]], nil
+	}},
+
+	"findGroups": {arity: 2, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		re, s, err := regexArgs("findGroups", args)
+		if err != nil {
+			return nil, err
+		}
+
+		loc := re.FindStringSubmatchIndex(s)
+		if loc == nil {
+			return nil, nil
+		}
+
+		groups := make([]interface{}, len(loc)/2)
+		for i := range groups {
+			if loc[2*i] >= 0 {
+				groups[i] = s[loc[2*i]:loc[2*i+1]]
+			}
+		}
+
+		return NewList(groups), nil
+	}},
+
+	"findAll": {arity: 2, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		re, s, err := regexArgs("findAll", args)
+		if err != nil {
+			return nil, err
+		}
+
+		return newStringList(re.FindAllString(s, -1)), nil
+	}},
+
+	"replaceAll": {arity: 3, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		re, s, err := regexArgs("replaceAll", args)
+		if err != nil {
+			return nil, err
+		}
+
+		replacement, err := stringArg("replaceAll", args, 2)
+		if err != nil {
+			return nil, err
+		}
+
+		return re.ReplaceAllString(s, replacement), nil
+	}},
+
+	"regexSplit": {arity: 2, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		re, s, err := regexArgs("regexSplit", args)
+		if err != nil {
+			return nil, err
+		}
+
+		return newStringList(re.Split(s, -1)), nil
+	}},
+}
+
+// maxCachedPatterns bounds the size of the cache of compiled patterns.
+const maxCachedPatterns = 256
+
+var patterns = struct {
+	sync.Mutex
+	compiled map[string]*regexp.Regexp
+}{compiled: map[string]*regexp.Regexp{}}
+
+// compile returns the compiled form of pattern, reusing it across calls.
+func compile(pattern string) (*regexp.Regexp, error) {
+	patterns.Lock()
+	defer patterns.Unlock()
+
+	if re, ok := patterns.compiled[pattern]; ok {
+		return re, nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(patterns.compiled) >= maxCachedPatterns {
+		patterns.compiled = map[string]*regexp.Regexp{}
+	}
+	patterns.compiled[pattern] = re
+
+	return re, nil
+}
+
+// regexArgs returns the compiled pattern and the string given as the first
+// two arguments of a regex native.
+func regexArgs(name string, args []interface{}) (*regexp.Regexp, string, error) {
+	pattern, s, err := twoStringArgs(name, args)
+	if err != nil {
+		return nil, "", err
+	}
+
+	re, err := compile(pattern)
+	if err != nil {
+		if e, ok := err.(*syntax.Error); ok {
+			return nil, "", nativeError{fmt.Sprintf("Invalid regular expression '%v': %v.", pattern, e.Code)}
+		}
+
+		return nil, "", nativeError{fmt.Sprintf("Invalid regular expression '%v'.", pattern)}
+	}
+
+	return re, s, nil
+}
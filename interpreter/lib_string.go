@@ -0,0 +1,211 @@
+package interpreter
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+var stringNatives = map[string]NativeFunction{
+	"len": {arity: 1, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		switch v := args[0].(type) {
+		case string:
+			return float64(utf8.RuneCountInString(v)), nil
+		case *List:
+			return float64(v.Len()), nil
+		case *Map:
+			return float64(v.Len()), nil
+		}
+
+		return nil, nativeError{"Argument of 'len' must be a string, a list or a map."}
+	}},
+
+	"substr": {arity: 3, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		s, err := stringArg("substr", args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		start, err := intArg("substr", args, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		end, err := intArg("substr", args, 2)
+		if err != nil {
+			return nil, err
+		}
+
+		runes := []rune(s)
+		if start < 0 || end < start || end > len(runes) {
+			return nil, nativeError{fmt.Sprintf("Substring bounds [%v, %v) out of range for length %v.", start, end, len(runes))}
+		}
+
+		return string(runes[start:end]), nil
+	}},
+
+	"indexOf": {arity: 2, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		s, sub, err := twoStringArgs("indexOf", args)
+		if err != nil {
+			return nil, err
+		}
+
+		i := strings.Index(s, sub)
+		if i < 0 {
+			return float64(-1), nil
+		}
+
+		return float64(utf8.RuneCountInString(s[:i])), nil
+	}},
+
+	"split": {arity: 2, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		s, sep, err := twoStringArgs("split", args)
+		if err != nil {
+			return nil, err
+		}
+
+		return newStringList(strings.Split(s, sep)), nil
+	}},
+
+	"join": {arity: 2, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		list, ok := args[0].(*List)
+		if !ok {
+			return nil, nativeError{"Argument 1 of 'join' must be a list."}
+		}
+
+		sep, err := stringArg("join", args, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		elements := list.Elements()
+		parts := make([]string, len(elements))
+		for i, e := range elements {
+			parts[i] = Stringify(e)
+		}
+
+		return strings.Join(parts, sep), nil
+	}},
+
+	"trim":  stringMapper("trim", strings.TrimSpace),
+	"upper": stringMapper("upper", strings.ToUpper),
+	"lower": stringMapper("lower", strings.ToLower),
+
+	"replace": {arity: 3, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		s, old, err := twoStringArgs("replace", args)
+		if err != nil {
+			return nil, err
+		}
+
+		new, err := stringArg("replace", args, 2)
+		if err != nil {
+			return nil, err
+		}
+
+		return strings.ReplaceAll(s, old, new), nil
+	}},
+
+	"startsWith": stringPredicate("startsWith", strings.HasPrefix),
+	"endsWith":   stringPredicate("endsWith", strings.HasSuffix),
+
+	"repeat": {arity: 2, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		s, err := stringArg("repeat", args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		n, err := intArg("repeat", args, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		if n < 0 {
+			return nil, nativeError{"Argument 2 of 'repeat' must not be negative."}
+		}
+
+		return strings.Repeat(s, n), nil
+	}},
+
+	"charAt": {arity: 2, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		s, err := stringArg("charAt", args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		i, err := intArg("charAt", args, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		runes := []rune(s)
+		if i < 0 || i >= len(runes) {
+			return nil, nativeError{fmt.Sprintf("Index %v out of range for length %v.", i, len(runes))}
+		}
+
+		return string(runes[i]), nil
+	}},
+
+	"ord": {arity: 1, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		s, err := stringArg("ord", args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		if utf8.RuneCountInString(s) != 1 {
+			return nil, nativeError{"Argument of 'ord' must be a single character."}
+		}
+
+		r, _ := utf8.DecodeRuneInString(s)
+
+		return float64(r), nil
+	}},
+
+	"chr": {arity: 1, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		n, err := intArg("chr", args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		if !utf8.ValidRune(rune(n)) || n != int(rune(n)) {
+			return nil, nativeError{fmt.Sprintf("%v is not a valid character code.", n)}
+		}
+
+		return string(rune(n)), nil
+	}},
+}
+
+func stringMapper(name string, f func(string) string) NativeFunction {
+	return NativeFunction{arity: 1, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		s, err := stringArg(name, args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		return f(s), nil
+	}}
+}
+
+func stringPredicate(name string, f func(string, string) bool) NativeFunction {
+	return NativeFunction{arity: 2, call: func(_ *Interpreter, args []interface{}) (interface{}, error) {
+		s, t, err := twoStringArgs(name, args)
+		if err != nil {
+			return nil, err
+		}
+
+		return f(s, t), nil
+	}}
+}
+
+func twoStringArgs(name string, args []interface{}) (string, string, error) {
+	a, err := stringArg(name, args, 0)
+	if err != nil {
+		return "", "", err
+	}
+
+	b, err := stringArg(name, args, 1)
+	if err != nil {
+		return "", "", err
+	}
+
+	return a, b, nil
+}
@@ -0,0 +1,48 @@
+package interpreter
+
+import (
+	"time"
+)
+
+var timeNatives = map[string]NativeFunction{
+	"clock": {arity: 0, call: func(i *Interpreter, _ []interface{}) (interface{}, error) {
+		return float64(i.clock.Now().UnixMilli()) / 1000, nil
+	}},
+
+	"now": {arity: 0, call: func(i *Interpreter, _ []interface{}) (interface{}, error) {
+		return float64(i.clock.Now().UnixMilli()), nil
+	}},
+
+	"timer": {arity: 0, call: func(i *Interpreter, _ []interface{}) (interface{}, error) {
+		return float64(i.clock.Now().Sub(i.started).Microseconds()) / 1000, nil
+	}},
+
+	"sleep": {arity: 1, call: func(i *Interpreter, args []interface{}) (interface{}, error) {
+		ms, err := numberArg("sleep", args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		if ms < 0 {
+			return nil, nativeError{"Sleep duration must not be negative."}
+		}
+
+		i.clock.Sleep(time.Duration(ms * float64(time.Millisecond)))
+		return nil, nil
+	}},
+
+	"formatTime": {arity: 2, call: func(i *Interpreter, args []interface{}) (interface{}, error) {
+		ts, err := numberArg("formatTime", args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		layout, err := stringArg("formatTime", args, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		t := time.UnixMilli(int64(ts)).In(i.clock.Now().Location())
+		return t.Format(layout), nil
+	}},
+}
@@ -0,0 +1,109 @@
+package interpreter
+
+import (
+	"strings"
+	"sync"
+)
+
+// List is the runtime value of a Lox list. It is shared by reference, also
+// between spawned tasks, so its elements are only accessed under mu.
+type List struct {
+	mu       sync.RWMutex
+	elements []interface{}
+	// frozen is set by the freeze native, after which the list can't change.
+	frozen bool
+}
+
+var errFrozenList = nativeError{"Can't modify a frozen list."}
+
+func NewList(elements []interface{}) *List {
+	return &List{elements: elements}
+}
+
+func newStringList(strings []string) *List {
+	elements := make([]interface{}, len(strings))
+	for i, s := range strings {
+		elements[i] = s
+	}
+
+	return NewList(elements)
+}
+
+// Elements returns a copy of the elements of the list.
+func (l *List) Elements() []interface{} {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return append([]interface{}{}, l.elements...)
+}
+
+func (l *List) Len() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return len(l.elements)
+}
+
+// at returns the element at index, which must be in range. Lists never
+// shrink, so an index checked against Len stays valid.
+func (l *List) at(index int) interface{} {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.elements[index]
+}
+
+func (l *List) setAt(index int, value interface{}) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.frozen {
+		return errFrozenList
+	}
+
+	l.elements[index] = value
+	return nil
+}
+
+func (l *List) push(value interface{}) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.frozen {
+		return errFrozenList
+	}
+
+	l.elements = append(l.elements, value)
+	return nil
+}
+
+// update runs f on the elements of the list while holding its lock.
+func (l *List) update(f func(elements []interface{})) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.frozen {
+		return errFrozenList
+	}
+
+	f(l.elements)
+	return nil
+}
+
+func (l *List) freeze() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.frozen = true
+}
+
+func (l *List) String() string {
+	elements := l.Elements()
+	parts := make([]string, len(elements))
+
+	for i, e := range elements {
+		parts[i] = Stringify(e)
+	}
+
+	return "[" + strings.Join(parts, ", ") + "]"
+}
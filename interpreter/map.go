@@ -0,0 +1,94 @@
+package interpreter
+
+import (
+	"strings"
+	"sync"
+)
+
+// Map is the runtime value of a Lox map from strings to values. It keeps
+// its keys in insertion order and is shared by reference, also between
+// spawned tasks, so its entries are only accessed under mu.
+type Map struct {
+	mu     sync.RWMutex
+	keys   []string
+	values map[string]interface{}
+	// frozen is set by the freeze native, after which the map can't change.
+	frozen bool
+}
+
+var errFrozenMap = nativeError{"Can't modify a frozen map."}
+
+func NewMap() *Map {
+	return &Map{keys: []string{}, values: map[string]interface{}{}}
+}
+
+func (m *Map) Get(key string) (interface{}, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	v, ok := m.values[key]
+	return v, ok
+}
+
+// Set stores value at key, even if the map is frozen.
+func (m *Map) Set(key string, value interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.store(key, value)
+}
+
+// put stores value at key unless the map is frozen.
+func (m *Map) put(key string, value interface{}) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.frozen {
+		return errFrozenMap
+	}
+
+	m.store(key, value)
+	return nil
+}
+
+func (m *Map) store(key string, value interface{}) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+
+	m.values[key] = value
+}
+
+// Keys returns a copy of the keys of the map, in insertion order.
+func (m *Map) Keys() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return append([]string{}, m.keys...)
+}
+
+func (m *Map) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return len(m.keys)
+}
+
+func (m *Map) freeze() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.frozen = true
+}
+
+func (m *Map) String() string {
+	keys := m.Keys()
+	parts := make([]string, len(keys))
+
+	for i, k := range keys {
+		v, _ := m.Get(k)
+		parts[i] = k + ": " + Stringify(v)
+	}
+
+	return "{" + strings.Join(parts, ", ") + "}"
+}
@@ -1,14 +1,22 @@
 package interpreter
 
+import (
+	"fmt"
+	"glox/environement"
+	"math"
+)
+
 type callFunction func(*Interpreter, []interface{}) (interface{}, error)
 
+// NativeFunction takes arity arguments, followed by up to optional ones.
 type NativeFunction struct {
     arity int
+    optional int
     call callFunction
 }
 
-func (n NativeFunction) Arity() int {
-    return n.arity
+func (n NativeFunction) Signature() Signature {
+    return Signature{Required: n.arity, Optional: n.optional}
 }
 
 func (n NativeFunction) Call(i *Interpreter, args []interface{}) (interface{}, error) {
@@ -18,3 +26,43 @@ func (n NativeFunction) Call(i *Interpreter, args []interface{}) (interface{}, e
 func (n NativeFunction) String() string {
     return "<native fn>"
 }
+
+// nativeError is returned by native functions for invalid arguments. The
+// interpreter reports it at the closing paren of the failing call.
+type nativeError struct {
+    message string
+}
+
+func (e nativeError) Error() string {
+    return e.message
+}
+
+func defineNatives(env *environement.Env, natives map[string]NativeFunction) {
+    for name, fn := range natives {
+        env.Define(name, fn)
+    }
+}
+
+func stringArg(name string, args []interface{}, i int) (string, error) {
+	if s, ok := args[i].(string); ok {
+		return s, nil
+	}
+
+	return "", nativeError{fmt.Sprintf("Argument %v of '%v' must be a string.", i+1, name)}
+}
+
+func numberArg(name string, args []interface{}, i int) (float64, error) {
+	if f, ok := args[i].(float64); ok {
+		return f, nil
+	}
+
+	return 0, nativeError{fmt.Sprintf("Argument %v of '%v' must be a number.", i+1, name)}
+}
+
+func intArg(name string, args []interface{}, i int) (int, error) {
+	if f, ok := args[i].(float64); ok && f == math.Trunc(f) && math.Abs(f) <= math.MaxInt32 {
+		return int(f), nil
+	}
+
+	return 0, nativeError{fmt.Sprintf("Argument %v of '%v' must be an integer.", i+1, name)}
+}
@@ -0,0 +1,24 @@
+package interpreter
+
+import "fmt"
+
+// Range is the lazy sequence of numbers returned by the range native.
+type Range struct {
+	start float64
+	end   float64
+	step  float64
+}
+
+// contains reports whether n is before the end of the range, in the
+// direction of its step.
+func (r *Range) contains(n float64) bool {
+	if r.step > 0 {
+		return n < r.end
+	}
+
+	return n > r.end
+}
+
+func (r *Range) String() string {
+	return fmt.Sprintf("range(%v, %v, %v)", formatNumber(r.start), formatNumber(r.end), formatNumber(r.step))
+}
@@ -9,3 +9,14 @@ type Return struct {
 func (r Return) Error() string {
     return fmt.Sprintf("%v", r.value)
 }
+
+// TailCall replaces a Return when the returned value is a call to a Lox
+// function, so that Function.Call can run it without growing the stack.
+type TailCall struct {
+    function Function
+    args []interface{}
+}
+
+func (t TailCall) Error() string {
+    return "tail call to " + t.function.String()
+}
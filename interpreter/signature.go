@@ -0,0 +1,104 @@
+package interpreter
+
+import (
+	"fmt"
+	"glox/ast"
+	"glox/errors"
+)
+
+// Signature describes the parameters of a Callable. The first Required
+// parameters must be given and the next Optional ones may be left out.
+// When Rest is set, the extra positional arguments are collected into a
+// list. Params holds the names of the parameters, and is empty for native
+// functions, which only take positional arguments.
+type Signature struct {
+	Params   []string
+	Required int
+	Optional int
+	Rest     bool
+}
+
+func (s Signature) String() string {
+	switch {
+	case s.Rest:
+		return fmt.Sprintf("at least %v", s.Required)
+	case s.Optional > 0:
+		return fmt.Sprintf("%v to %v", s.Required, s.Required+s.Optional)
+	}
+
+	return fmt.Sprint(s.Required)
+}
+
+// absentArgument marks an optional parameter without an argument, whose
+// default value is evaluated when the function runs.
+type absentArgument struct{}
+
+var absent = absentArgument{}
+
+// bind matches the arguments of a call with the signature of the function
+// it calls, and returns one value per parameter.
+func (i *Interpreter) bind(function Callable, e *ast.Call, positional []interface{}, named []interface{}) ([]interface{}, error) {
+	sig := function.Signature()
+	fixed := sig.Required + sig.Optional
+
+	if count := len(positional) + len(named); (count > fixed && !sig.Rest) || count < sig.Required {
+		return nil, errors.NewRuntimeErr(e.Paren, fmt.Sprintf("Expected %v arguments but got %v.", sig, count))
+	}
+
+	if len(sig.Params) == 0 {
+		if len(named) > 0 {
+			return nil, errors.NewRuntimeErr(e.NamedArguments[0].Name, "Native functions don't take named arguments.")
+		}
+
+		return positional, nil
+	}
+
+	args := make([]interface{}, fixed, len(sig.Params))
+	for j := range args {
+		args[j] = absent
+	}
+
+	copy(args, positional)
+
+	for j, arg := range e.NamedArguments {
+		name := arg.Name.Lexeme()
+		index := indexOf(sig.Params[:fixed], name)
+
+		if index < 0 {
+			return nil, errors.NewRuntimeErr(arg.Name, fmt.Sprintf("Unknown argument '%v'.", name))
+		}
+
+		if args[index] != absent {
+			return nil, errors.NewRuntimeErr(arg.Name, fmt.Sprintf("Argument '%v' is given twice.", name))
+		}
+
+		args[index] = named[j]
+	}
+
+	for j := 0; j < sig.Required; j++ {
+		if args[j] == absent {
+			return nil, errors.NewRuntimeErr(e.Paren, fmt.Sprintf("Missing argument '%v'.", sig.Params[j]))
+		}
+	}
+
+	if sig.Rest {
+		rest := []interface{}{}
+		if len(positional) > fixed {
+			rest = append(rest, positional[fixed:]...)
+		}
+
+		args = append(args, NewList(rest))
+	}
+
+	return args, nil
+}
+
+func indexOf(names []string, name string) int {
+	for j, n := range names {
+		if n == name {
+			return j
+		}
+	}
+
+	return -1
+}
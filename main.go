@@ -1,96 +1,167 @@
 package main
 
 import (
-	"bufio"
+	"flag"
 	"fmt"
+	"glox/ast"
 	"glox/errors"
 	"glox/interpreter"
-	"glox/parser"
+	"glox/optimizer"
 	"glox/resolver"
-	"glox/scanner"
-	"io"
 	"os"
+	"strconv"
+	"strings"
 )
 
-var interp = interpreter.NewInterpreter()
+var interp = newInterpreter()
+
+var tailCalls = true
+
+// allowFS lists the directories given with --allow-fs.
+var allowFS pathList
+
+// seed is the --seed of the random number generator, if given.
+var seed *int64
+
+var subcommands = map[string]func(args []string) int{
+    "check": checkCommand,
+    "doc": docCommand,
+    "parse": parseCommand,
+    "tokens": tokensCommand,
+}
 
 func main() {
     args := os.Args
 
-    if len(args) > 2 {
-        fmt.Println("Usage: glox [script]")
-        os.Exit(64)
-    } else if len(args) == 2 {
-        err := runFile(args[1])
+    if len(args) > 1 {
+        if cmd, ok := subcommands[args[1]]; ok {
+            os.Exit(cmd(args[2:]))
+        }
+    }
+
+    noTailCalls := flag.Bool("no-tail-calls", false, "keep a stack frame for every call, for debugging")
+    flag.Var(&allowFS, "allow-fs", "let scripts access files under `path` (repeatable)")
+    flag.Func("seed", "seed the random number generator with `n` for reproducible runs", func(s string) error {
+        n, err := strconv.ParseInt(s, 10, 64)
+        seed = &n
+        return err
+    })
+    flag.Usage = usage
+    flag.Parse()
+
+    tailCalls = !*noTailCalls
+    interp = newInterpreter()
+
+    if flag.NArg() >= 1 {
+        interp.SetArgs(flag.Args()[1:])
+
+        err := runFile(flag.Arg(0))
         if err != nil {
             panic(err)
         }
     } else {
         runPrompt()
+
+        if code, ok := interp.Exited(); ok {
+            os.Exit(code)
+        }
     }
 }
 
-func runFile(path string) error {
-    b, err := os.ReadFile(path)
+func usage() {
+    fmt.Fprintln(flag.CommandLine.Output(), "Usage: glox [flags] [script [arguments...]]")
+    flag.PrintDefaults()
+}
 
-    if err != nil {
-        return err
-    }
+// newInterpreter creates an interpreter configured from the command line.
+func newInterpreter() interpreter.Interpreter {
+    i := interpreter.NewInterpreter()
+    i.SetTailCalls(tailCalls)
 
-    run(string(b))
+    if seed != nil {
+        i.Seed(*seed)
+    }
 
-    if errors.HadError {
-        os.Exit(65)
+    if err := i.AllowFS(allowFS...); err != nil {
+        fmt.Fprintln(os.Stderr, err)
+        os.Exit(64)
     }
 
-    if errors.HadRuntimeError {
-        os.Exit(70)
+    return i
+}
+
+// pathList is a flag that can be given several times.
+type pathList []string
+
+func (l *pathList) String() string {
+    return strings.Join(*l, ",")
+}
+
+func (l *pathList) Set(path string) error {
+    if _, err := os.Stat(path); err != nil {
+        return err
     }
 
+    *l = append(*l, path)
     return nil
 }
 
-func runPrompt() error {
-    reader := bufio.NewReader(os.Stdin)
-
-    for {
-        fmt.Print("> ")
-        line, err := reader.ReadString('\n')
+func runFile(path string) error {
+    b, err := os.ReadFile(path)
 
-        if err == io.EOF {
-            break
-        }
+    if err != nil {
+        return err
+    }
 
+    if strings.HasSuffix(path, ".json") {
+        statements, err := ast.UnmarshalJSON(b)
         if err != nil {
             return err
         }
 
-        run(line)
+        runStatements(statements)
+    } else {
+        run(string(b))
+    }
+
+    if code, ok := interp.Exited(); ok {
+        os.Exit(code)
+    }
+
+    // The program ends with its last task, whose runtime errors count
+    // towards the exit status.
+    interp.WaitTasks()
 
-        errors.HadError = false
+    if errors.HadError() {
+        os.Exit(65)
     }
-    
-    fmt.Print("\n")
+
+    if errors.HadRuntimeError() {
+        os.Exit(70)
+    }
+
     return nil
 }
 
 func run(source string) {
-    s := scanner.NewScanner(source)
-    tokens := s.ScanTokens()
-
-    p := parser.NewParser(tokens)
-    statements := p.Parse()
+    statements := parseSource(source)
 
-    if errors.HadError {
+    if errors.HadError() {
         return
     }
 
+    runStatements(statements)
+}
+
+func runStatements(statements []ast.Stmt) {
     res := resolver.NewResolver(&interp)
     res.Resolve(statements)
 
-    if errors.HadError {
+    if errors.HadError() {
         return
     }
 
+    statements = optimizer.NewOptimizer().Optimize(statements)
+
     interp.Interpret(statements)
 }
@@ -0,0 +1,320 @@
+package optimizer
+
+import (
+	"glox/ast"
+	"glox/interpreter"
+	"glox/token"
+)
+
+// Optimizer folds constant expressions and drops statements that can never
+// run. It must run after the resolver: folded nodes only ever replace trees
+// built from literals, so every resolved variable node is kept as is.
+type Optimizer struct {
+	result ast.Stmt
+}
+
+// folder evaluates the folded expressions. Their operands are literals, so
+// a single interpreter can be shared by every optimizer.
+var folder = interpreter.NewInterpreter()
+
+func NewOptimizer() *Optimizer {
+	return &Optimizer{}
+}
+
+func (o *Optimizer) Optimize(statements []ast.Stmt) []ast.Stmt {
+	return o.statements(statements)
+}
+
+func (o *Optimizer) statements(statements []ast.Stmt) []ast.Stmt {
+	optimized := []ast.Stmt{}
+
+	for _, s := range statements {
+		s = o.stmt(s)
+		if s == nil {
+			continue
+		}
+
+		optimized = append(optimized, s)
+
+		if _, ok := s.(*ast.Return); ok {
+			break
+		}
+	}
+
+	return optimized
+}
+
+func (o *Optimizer) stmt(s ast.Stmt) ast.Stmt {
+	if s == nil {
+		return nil
+	}
+
+	s.Accept(o)
+
+	return o.result
+}
+
+// body optimizes a statement that cannot be left out of its parent.
+func (o *Optimizer) body(s ast.Stmt) ast.Stmt {
+	if s = o.stmt(s); s == nil {
+		return ast.NewBlock([]ast.Stmt{})
+	}
+
+	return s
+}
+
+func (o *Optimizer) expr(e ast.Expr) ast.Expr {
+	if e == nil {
+		return nil
+	}
+
+	optimized, _ := e.Accept(o)
+
+	return optimized.(ast.Expr)
+}
+
+// fold evaluates e when all of its operands are literals. Expressions that
+// fail, such as a division by zero, are kept so the error is still reported
+// at run time, at its original line.
+func (o *Optimizer) fold(e ast.Expr, operands ...ast.Expr) ast.Expr {
+	for _, operand := range operands {
+		if _, ok := operand.(*ast.Literal); !ok {
+			return e
+		}
+	}
+
+	val, err := folder.Evaluate(e)
+	if err != nil {
+		return e
+	}
+
+	return ast.NewLiteral(val)
+}
+
+func (o *Optimizer) VisitAssignExpr(e *ast.Assign) (interface{}, error) {
+	e.Value = o.expr(e.Value)
+
+	return e, nil
+}
+
+func (o *Optimizer) VisitBinaryExpr(e *ast.Binary) (interface{}, error) {
+	e.Left = o.expr(e.Left)
+	e.Right = o.expr(e.Right)
+
+	return o.fold(e, e.Left, e.Right), nil
+}
+
+func (o *Optimizer) VisitCallExpr(e *ast.Call) (interface{}, error) {
+	e.Callee = o.expr(e.Callee)
+
+	for i, arg := range e.Arguments {
+		e.Arguments[i] = o.expr(arg)
+	}
+
+	for i := range e.NamedArguments {
+		e.NamedArguments[i].Value = o.expr(e.NamedArguments[i].Value)
+	}
+
+	return e, nil
+}
+
+func (o *Optimizer) VisitCoalesceExpr(e *ast.Coalesce) (interface{}, error) {
+	e.Left = o.expr(e.Left)
+	e.Right = o.expr(e.Right)
+
+	left, ok := e.Left.(*ast.Literal)
+	if !ok {
+		return e, nil
+	}
+
+	if left.Value != nil {
+		return left, nil
+	}
+
+	return e.Right, nil
+}
+
+func (o *Optimizer) VisitConditionalExpr(e *ast.Conditional) (interface{}, error) {
+	e.Condition = o.expr(e.Condition)
+	e.ThenBranch = o.expr(e.ThenBranch)
+	e.ElseBranch = o.expr(e.ElseBranch)
+
+	c, ok := e.Condition.(*ast.Literal)
+	if !ok {
+		return e, nil
+	}
+
+	if interpreter.IsTruthy(c.Value) {
+		return e.ThenBranch, nil
+	}
+
+	return e.ElseBranch, nil
+}
+
+func (o *Optimizer) VisitGroupingExpr(e *ast.Grouping) (interface{}, error) {
+	e.Expression = o.expr(e.Expression)
+
+	if l, ok := e.Expression.(*ast.Literal); ok {
+		return l, nil
+	}
+
+	return e, nil
+}
+
+func (o *Optimizer) VisitIncrementExpr(e *ast.Increment) (interface{}, error) {
+	return e, nil
+}
+
+func (o *Optimizer) VisitInterpolationExpr(e *ast.Interpolation) (interface{}, error) {
+	for i, part := range e.Parts {
+		e.Parts[i] = o.expr(part)
+	}
+
+	return o.fold(e, e.Parts...), nil
+}
+
+func (o *Optimizer) VisitLiteralExpr(e *ast.Literal) (interface{}, error) {
+	return e, nil
+}
+
+func (o *Optimizer) VisitLogicalExpr(e *ast.Logical) (interface{}, error) {
+	e.Left = o.expr(e.Left)
+	e.Right = o.expr(e.Right)
+
+	left, ok := e.Left.(*ast.Literal)
+	if !ok {
+		return e, nil
+	}
+
+	if (e.Operator.Type() == token.OR) == interpreter.IsTruthy(left.Value) {
+		return left, nil
+	}
+
+	return e.Right, nil
+}
+
+func (o *Optimizer) VisitUnaryExpr(e *ast.Unary) (interface{}, error) {
+	e.Right = o.expr(e.Right)
+
+	return o.fold(e, e.Right), nil
+}
+
+func (o *Optimizer) VisitVariableExpr(e *ast.Variable) (interface{}, error) {
+	return e, nil
+}
+
+func (o *Optimizer) VisitBlockStmt(s *ast.Block) error {
+	s.Statements = o.statements(s.Statements)
+	o.result = s
+
+	return nil
+}
+
+func (o *Optimizer) VisitExpressionStmt(s *ast.Expression) error {
+	s.Exp = o.expr(s.Exp)
+	o.result = s
+
+	return nil
+}
+
+func (o *Optimizer) VisitForInStmt(s *ast.ForIn) error {
+	s.Iterable = o.expr(s.Iterable)
+	s.Body = o.body(s.Body)
+	o.result = s
+
+	return nil
+}
+
+func (o *Optimizer) VisitFunctionStmt(s *ast.Function) error {
+	for i := range s.Params {
+		s.Params[i].Default = o.expr(s.Params[i].Default)
+	}
+
+	s.Body = o.statements(s.Body)
+	o.result = s
+
+	return nil
+}
+
+func (o *Optimizer) VisitIfStmt(s *ast.If) error {
+	s.Condition = o.expr(s.Condition)
+	s.ThenBranch = o.body(s.ThenBranch)
+	s.ElseBranch = o.stmt(s.ElseBranch)
+
+	o.result = s
+
+	if c, ok := s.Condition.(*ast.Literal); ok {
+		if interpreter.IsTruthy(c.Value) {
+			o.result = s.ThenBranch
+		} else {
+			o.result = s.ElseBranch
+		}
+	}
+
+	return nil
+}
+
+func (o *Optimizer) VisitPrintStmt(s *ast.Print) error {
+	s.Exp = o.expr(s.Exp)
+	o.result = s
+
+	return nil
+}
+
+func (o *Optimizer) VisitReturnStmt(s *ast.Return) error {
+	s.Value = o.expr(s.Value)
+	o.result = s
+
+	return nil
+}
+
+func (o *Optimizer) VisitSelectStmt(s *ast.Select) error {
+	for i := range s.Cases {
+		c := &s.Cases[i]
+		c.Channel = o.expr(c.Channel)
+		c.Value = o.expr(c.Value)
+		c.Body = o.body(c.Body)
+	}
+
+	if s.Default != nil {
+		s.Default = o.body(s.Default)
+	}
+
+	o.result = s
+
+	return nil
+}
+
+func (o *Optimizer) VisitSpawnStmt(s *ast.Spawn) error {
+	o.expr(s.Call)
+	o.result = s
+
+	return nil
+}
+
+func (o *Optimizer) VisitVarStmt(s *ast.Var) error {
+	s.Initializer = o.expr(s.Initializer)
+	o.result = s
+
+	return nil
+}
+
+func (o *Optimizer) VisitYieldStmt(s *ast.Yield) error {
+	s.Value = o.expr(s.Value)
+	o.result = s
+
+	return nil
+}
+
+func (o *Optimizer) VisitWhileStmt(s *ast.While) error {
+	s.Condition = o.expr(s.Condition)
+	s.Body = o.body(s.Body)
+
+	o.result = s
+
+	if c, ok := s.Condition.(*ast.Literal); ok && !interpreter.IsTruthy(c.Value) {
+		o.result = nil
+	}
+
+	return nil
+}
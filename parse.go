@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"glox/ast"
+	"glox/errors"
+	"glox/optimizer"
+	"glox/parser"
+	"glox/scanner"
+	"os"
+)
+
+func parseCommand(args []string) int {
+	fs := flag.NewFlagSet("parse", flag.ExitOnError)
+	format := fs.String("format", "sexpr", "output format: sexpr or json")
+	input := fs.String("input", "lox", "input format: lox or json")
+	optimize := fs.Bool("optimize", false, "fold constants and remove dead code first")
+
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: glox parse [--format=sexpr|json] [--input=lox|json] [--optimize] file")
+		fs.PrintDefaults()
+	}
+
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 64
+	}
+
+	b, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 66
+	}
+
+	var statements []ast.Stmt
+
+	switch *input {
+	case "lox":
+		statements = parseSource(string(b))
+	case "json":
+		statements, err = ast.UnmarshalJSON(b)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 65
+		}
+	default:
+		fs.Usage()
+		return 64
+	}
+
+	if errors.HadError() {
+		return 65
+	}
+
+	if *optimize {
+		statements = optimizer.NewOptimizer().Optimize(statements)
+	}
+
+	switch *format {
+	case "sexpr":
+		fmt.Println(ast.NewPrinter().Print(statements))
+	case "json":
+		out, err := ast.MarshalJSON(statements, "  ")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 70
+		}
+
+		fmt.Println(string(out))
+	default:
+		fs.Usage()
+		return 64
+	}
+
+	return 0
+}
+
+func parseSource(source string) []ast.Stmt {
+	s := scanner.NewScanner(source)
+	p := parser.NewParser(s.ScanTokens())
+
+	return p.Parse()
+}
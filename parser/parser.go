@@ -5,15 +5,45 @@ import (
 	"glox/ast"
 	"glox/errors"
 	"glox/token"
+	"strings"
 )
 
 type Parser struct {
 	tokens  []token.Token
 	current int
+	// docs maps the index of a token to the doc comments preceding it.
+	docs map[int]string
+}
+
+// compoundOperators maps compound assignment operators to the binary
+// operator they apply.
+var compoundOperators = map[token.TokenType]token.TokenType{
+	token.PLUS_EQUAL:    token.PLUS,
+	token.MINUS_EQUAL:   token.MINUS,
+	token.STAR_EQUAL:    token.STAR,
+	token.SLASH_EQUAL:   token.SLASH,
+	token.PERCENT_EQUAL: token.PERCENT,
 }
 
 func NewParser(tokens []token.Token) Parser {
-	return Parser{tokens: tokens, current: 0}
+	filtered := []token.Token{}
+	docs := map[int]string{}
+
+	for _, t := range tokens {
+		switch t.Type() {
+		case token.DOC_COMMENT:
+			if doc, ok := docs[len(filtered)]; ok {
+				docs[len(filtered)] = doc + "\n" + t.Literal().(string)
+			} else {
+				docs[len(filtered)] = t.Literal().(string)
+			}
+		case token.COMMENT, token.WHITESPACE:
+		default:
+			filtered = append(filtered, t)
+		}
+	}
+
+	return Parser{tokens: filtered, current: 0, docs: docs}
 }
 
 func (p *Parser) Parse() []ast.Stmt {
@@ -26,6 +56,21 @@ func (p *Parser) Parse() []ast.Stmt {
 	return statements
 }
 
+// ParseExpression parses tokens that must form a single expression.
+func (p *Parser) ParseExpression() ast.Expr {
+	expr, err := p.expression()
+	if err != nil {
+		return nil
+	}
+
+	if !p.isAtEnd() {
+		p.error(p.peek(), "Expect end of expression.")
+		return nil
+	}
+
+	return expr
+}
+
 func (p *Parser) declaration() ast.Stmt {
 	var stmt ast.Stmt
 	var err error
@@ -34,6 +79,8 @@ func (p *Parser) declaration() ast.Stmt {
 		stmt, err = p.function("function")
 	} else if p.match(token.VAR) {
 		stmt, err = p.varDeclaration()
+	} else if p.match(token.CONST) {
+		stmt, err = p.constDeclaration()
 	} else {
 		stmt, err = p.statement()
 	}
@@ -47,6 +94,7 @@ func (p *Parser) declaration() ast.Stmt {
 }
 
 func (p *Parser) function(kind string) (ast.Stmt, error) {
+	doc := p.docComment()
 	name, err := p.consume(token.IDENTIFIER, "Expect "+kind+" name.")
 	if err != nil {
 		return nil, err
@@ -57,21 +105,9 @@ func (p *Parser) function(kind string) (ast.Stmt, error) {
 		return nil, err
 	}
 
-    params := []token.Token{}
-
-    if !p.check(token.RIGHT_PAREN) {
-        for ok := true; ok; ok = p.match(token.COMMA) {
-            if len(params) >= 255 {
-                errors.Error(p.peek(), "Can't have more than 255 parameters.")
-            }
-
-            t, err := p.consume(token.IDENTIFIER, "Expect parameter name.")
-            if err != nil {
-                return nil, err
-            }
-
-            params = append(params, t)
-        }
+    params, err := p.parameters()
+    if err != nil {
+        return nil, err
     }
 
     _, err = p.consume(token.RIGHT_PAREN, "Expect ')' after parameters.")
@@ -79,7 +115,11 @@ func (p *Parser) function(kind string) (ast.Stmt, error) {
         return nil, err
     }
 
-    
+    returnType, err := p.typeAnnotation()
+    if err != nil {
+        return nil, err
+    }
+
     _, err = p.consume(token.LEFT_BRACE, "Expect '{' before "+kind+" body.")
     if err != nil {
         return nil, err
@@ -90,7 +130,58 @@ func (p *Parser) function(kind string) (ast.Stmt, error) {
         return nil, err
     }
 
-    return ast.NewFunction(name, params, body), nil
+    return ast.NewFunction(name, params, returnType, body, doc), nil
+}
+
+// parameters parses a parameter list, where each parameter may have a type
+// annotation and a default value, and the last one may be a `...rest`
+// parameter. Parameters without a default can't follow one with a default.
+func (p *Parser) parameters() ([]ast.Param, error) {
+    params := []ast.Param{}
+
+    if p.check(token.RIGHT_PAREN) {
+        return params, nil
+    }
+
+    for ok := true; ok; ok = p.match(token.COMMA) {
+        if len(params) >= 255 {
+            errors.Error(p.peek(), "Can't have more than 255 parameters.")
+        }
+
+        if len(params) > 0 && params[len(params)-1].Rest {
+            errors.Error(p.peek(), "A rest parameter must be the last parameter.")
+        }
+
+        rest := p.match(token.ELLIPSIS)
+
+        t, err := p.consume(token.IDENTIFIER, "Expect parameter name.")
+        if err != nil {
+            return nil, err
+        }
+
+        typ, err := p.typeAnnotation()
+        if err != nil {
+            return nil, err
+        }
+
+        var value ast.Expr
+        if p.match(token.EQUAL) {
+            if rest {
+                errors.Error(p.previous(), "A rest parameter can't have a default value.")
+            }
+
+            value, err = p.expression()
+            if err != nil {
+                return nil, err
+            }
+        } else if !rest && len(params) > 0 && params[len(params)-1].Default != nil {
+            errors.Error(t, "Expect a default value after a parameter with a default value.")
+        }
+
+        params = append(params, ast.Param{Name: t, Type: typ, Default: value, Rest: rest})
+    }
+
+    return params, nil
 }
 
 func (p *Parser) statement() (ast.Stmt, error) {
@@ -114,6 +205,18 @@ func (p *Parser) statement() (ast.Stmt, error) {
         return p.returnStatement()
     }
 
+	if p.match(token.YIELD) {
+		return p.yieldStatement()
+	}
+
+	if p.match(token.SPAWN) {
+		return p.spawnStatement()
+	}
+
+	if p.match(token.SELECT) {
+		return p.selectStatement()
+	}
+
 	if p.match(token.LEFT_BRACE) {
 		block, err := p.block()
 
@@ -195,6 +298,10 @@ func (p *Parser) forStatement() (ast.Stmt, error) {
 		return nil, err
 	}
 
+	if p.checkForIn() {
+		return p.forInStatement()
+	}
+
 	var initializer ast.Stmt
 	if p.match(token.SEMICOLON) {
 		initializer = nil
@@ -270,6 +377,40 @@ func (p *Parser) forStatement() (ast.Stmt, error) {
 	return body, nil
 }
 
+// checkForIn reports whether the for clauses start with `var name in`.
+// 'in' is not a keyword, so that it remains a valid identifier elsewhere.
+func (p Parser) checkForIn() bool {
+	if !p.check(token.VAR) || p.current+2 >= len(p.tokens) {
+		return false
+	}
+
+	in := p.tokens[p.current+2]
+
+	return p.tokens[p.current+1].Type() == token.IDENTIFIER && in.Type() == token.IDENTIFIER && in.Lexeme() == "in"
+}
+
+func (p *Parser) forInStatement() (ast.Stmt, error) {
+	p.advance()
+	name := p.advance()
+	keyword := p.advance()
+
+	iterable, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := p.consume(token.RIGHT_PAREN, "Expect ')' after for clauses."); err != nil {
+		return nil, err
+	}
+
+	body, err := p.statement()
+	if err != nil {
+		return nil, err
+	}
+
+	return ast.NewForIn(name, keyword, iterable, body), nil
+}
+
 func (p *Parser) printStatement() (ast.Stmt, error) {
 	val, err := p.expression()
 	if err != nil {
@@ -304,6 +445,161 @@ func (p *Parser) returnStatement() (ast.Stmt, error) {
     return ast.NewReturn(keyword, value), nil
 }
 
+func (p *Parser) yieldStatement() (ast.Stmt, error) {
+	keyword := p.previous()
+
+	var value ast.Expr
+	if !p.check(token.SEMICOLON) {
+		v, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+
+		value = v
+	}
+
+	if _, err := p.consume(token.SEMICOLON, "Expect ';' after yield value."); err != nil {
+		return nil, err
+	}
+
+	return ast.NewYield(keyword, value), nil
+}
+
+func (p *Parser) spawnStatement() (ast.Stmt, error) {
+	keyword := p.previous()
+
+	expr, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+
+	call, ok := expr.(*ast.Call)
+	if !ok {
+		return nil, p.error(keyword, "Expect function call after 'spawn'.")
+	}
+
+	if _, err := p.consume(token.SEMICOLON, "Expect ';' after spawned call."); err != nil {
+		return nil, err
+	}
+
+	return ast.NewSpawn(keyword, call), nil
+}
+
+func (p *Parser) selectStatement() (ast.Stmt, error) {
+	keyword := p.previous()
+
+	if _, err := p.consume(token.LEFT_BRACE, "Expect '{' after 'select'."); err != nil {
+		return nil, err
+	}
+
+	cases := []ast.SelectCase{}
+	var defaultCase ast.Stmt
+
+	for !p.check(token.RIGHT_BRACE) && !p.isAtEnd() {
+		if p.match(token.DEFAULT) {
+			if defaultCase != nil {
+				return nil, p.error(p.previous(), "Already a default case in this select.")
+			}
+
+			if _, err := p.consume(token.COLON, "Expect ':' after 'default'."); err != nil {
+				return nil, err
+			}
+
+			body, err := p.statement()
+			if err != nil {
+				return nil, err
+			}
+
+			defaultCase = body
+			continue
+		}
+
+		if _, err := p.consume(token.CASE, "Expect 'case' or 'default' in select."); err != nil {
+			return nil, err
+		}
+
+		c, err := p.selectCase()
+		if err != nil {
+			return nil, err
+		}
+
+		cases = append(cases, c)
+	}
+
+	if _, err := p.consume(token.RIGHT_BRACE, "Expect '}' after select cases."); err != nil {
+		return nil, err
+	}
+
+	if len(cases) == 0 && defaultCase == nil {
+		return nil, p.error(keyword, "Expect at least one case in select.")
+	}
+
+	return ast.NewSelect(keyword, cases, defaultCase), nil
+}
+
+// selectCase parses `[var name =] receive(channel): body` or
+// `send(channel, value): body`, after the 'case' keyword.
+func (p *Parser) selectCase() (ast.SelectCase, error) {
+	var c ast.SelectCase
+
+	if p.match(token.VAR) {
+		name, err := p.consume(token.IDENTIFIER, "Expect variable name.")
+		if err != nil {
+			return c, err
+		}
+
+		if _, err := p.consume(token.EQUAL, "Expect '=' after variable name."); err != nil {
+			return c, err
+		}
+
+		c.Name = &name
+	}
+
+	operation, err := p.consume(token.IDENTIFIER, "Expect 'send' or 'receive' after 'case'.")
+	if err != nil {
+		return c, err
+	}
+
+	c.Operation = operation
+
+	switch {
+	case operation.Lexeme() == "send" && c.Name != nil:
+		return c, p.error(operation, "Only the value of a receive can be assigned.")
+	case operation.Lexeme() != "send" && operation.Lexeme() != "receive":
+		return c, p.error(operation, "Expect 'send' or 'receive' after 'case'.")
+	}
+
+	if _, err := p.consume(token.LEFT_PAREN, "Expect '(' after '"+operation.Lexeme()+"'."); err != nil {
+		return c, err
+	}
+
+	if c.Channel, err = p.expression(); err != nil {
+		return c, err
+	}
+
+	if operation.Lexeme() == "send" {
+		if _, err := p.consume(token.COMMA, "Expect ',' after channel."); err != nil {
+			return c, err
+		}
+
+		if c.Value, err = p.expression(); err != nil {
+			return c, err
+		}
+	}
+
+	if _, err := p.consume(token.RIGHT_PAREN, "Expect ')' after arguments."); err != nil {
+		return c, err
+	}
+
+	if _, err := p.consume(token.COLON, "Expect ':' after case."); err != nil {
+		return c, err
+	}
+
+	c.Body, err = p.statement()
+
+	return c, err
+}
+
 func (p *Parser) block() ([]ast.Stmt, error) {
 	statements := []ast.Stmt{}
 
@@ -319,12 +615,18 @@ func (p *Parser) block() ([]ast.Stmt, error) {
 }
 
 func (p *Parser) varDeclaration() (ast.Stmt, error) {
+	doc := p.docComment()
 	name, err := p.consume(token.IDENTIFIER, "Expect variable name.")
 
 	if err != nil {
 		return nil, err
 	}
 
+	typ, err := p.typeAnnotation()
+	if err != nil {
+		return nil, err
+	}
+
 	var initializer ast.Expr
 	if p.match(token.EQUAL) {
 		initializer, err = p.expression()
@@ -340,7 +642,52 @@ func (p *Parser) varDeclaration() (ast.Stmt, error) {
 		return nil, err
 	}
 
-	return ast.NewVar(name, initializer), nil
+	return ast.NewVar(name, typ, initializer, doc, false), nil
+}
+
+// constDeclaration parses `const name = value;`, which must be initialized.
+func (p *Parser) constDeclaration() (ast.Stmt, error) {
+	doc := p.docComment()
+	name, err := p.consume(token.IDENTIFIER, "Expect constant name.")
+	if err != nil {
+		return nil, err
+	}
+
+	typ, err := p.typeAnnotation()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.consume(token.EQUAL, "Expect '=' after constant name.")
+	if err != nil {
+		return nil, err
+	}
+
+	initializer, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.consume(token.SEMICOLON, "Expect ';' after constant declaration.")
+	if err != nil {
+		return nil, err
+	}
+
+	return ast.NewVar(name, typ, initializer, doc, true), nil
+}
+
+// typeAnnotation parses an optional `: type` annotation, where the type is
+// a name optionally followed by '?' to allow nil.
+func (p *Parser) typeAnnotation() (*ast.Type, error) {
+	if !p.match(token.COLON) {
+		return nil, nil
+	}
+
+	if !p.match(token.IDENTIFIER, token.NIL, token.FUN) {
+		return nil, p.error(p.peek(), "Expect type name.")
+	}
+
+	return &ast.Type{Name: p.previous(), Nullable: p.match(token.QUESTION)}, nil
 }
 
 func (p *Parser) expressionStatement() (ast.Stmt, error) {
@@ -362,7 +709,7 @@ func (p *Parser) expression() (ast.Expr, error) {
 }
 
 func (p *Parser) assignement() (ast.Expr, error) {
-	expr, err := p.or()
+	expr, err := p.conditional()
 
 	if err != nil {
 		return nil, err
@@ -382,6 +729,73 @@ func (p *Parser) assignement() (ast.Expr, error) {
 		}
 
 		errors.Error(equals, "Invalid assignement target.")
+	} else if p.match(token.PLUS_EQUAL, token.MINUS_EQUAL, token.STAR_EQUAL, token.SLASH_EQUAL, token.PERCENT_EQUAL) {
+		operator := p.previous()
+		value, err := p.assignement()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if variable, ok := expr.(*ast.Variable); ok {
+			name := variable.Name
+			op := token.NewToken(compoundOperators[operator.Type()], operator.Lexeme(), nil, operator.Line(), operator.Column())
+
+			return ast.NewAssign(name, ast.NewBinary(ast.NewVariable(name), op, value)), nil
+		}
+
+		errors.Error(operator, "Invalid assignement target.")
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) conditional() (ast.Expr, error) {
+	expr, err := p.coalesce()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(token.QUESTION) {
+		thenBranch, err := p.expression()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := p.consume(token.COLON, "Expect ':' after then branch of conditional expression."); err != nil {
+			return nil, err
+		}
+
+		elseBranch, err := p.conditional()
+
+		if err != nil {
+			return nil, err
+		}
+
+		expr = ast.NewConditional(expr, thenBranch, elseBranch)
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) coalesce() (ast.Expr, error) {
+	expr, err := p.or()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(token.QUESTION_QUESTION) {
+		operator := p.previous()
+		right, err := p.or()
+
+		if err != nil {
+			return nil, err
+		}
+
+		expr = ast.NewCoalesce(expr, operator, right)
 	}
 
 	return expr, nil
@@ -451,13 +865,97 @@ func (p *Parser) equality() (ast.Expr, error) {
 }
 
 func (p *Parser) comparison() (ast.Expr, error) {
-	expr, err := p.term()
+	expr, err := p.bitOr()
 
 	if err != nil {
 		return nil, err
 	}
 
 	for p.match(token.LESS, token.LESS_EQUAL, token.GREATER, token.GREATER_EQUAL) {
+		op := p.previous()
+		right, err := p.bitOr()
+
+		if err != nil {
+			return nil, err
+		}
+
+		expr = ast.NewBinary(expr, op, right)
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) bitOr() (ast.Expr, error) {
+	expr, err := p.bitXor()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(token.PIPE) {
+		op := p.previous()
+		right, err := p.bitXor()
+
+		if err != nil {
+			return nil, err
+		}
+
+		expr = ast.NewBinary(expr, op, right)
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) bitXor() (ast.Expr, error) {
+	expr, err := p.bitAnd()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(token.CARET) {
+		op := p.previous()
+		right, err := p.bitAnd()
+
+		if err != nil {
+			return nil, err
+		}
+
+		expr = ast.NewBinary(expr, op, right)
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) bitAnd() (ast.Expr, error) {
+	expr, err := p.shift()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(token.AMPERSAND) {
+		op := p.previous()
+		right, err := p.shift()
+
+		if err != nil {
+			return nil, err
+		}
+
+		expr = ast.NewBinary(expr, op, right)
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) shift() (ast.Expr, error) {
+	expr, err := p.term()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(token.LESS_LESS, token.GREATER_GREATER) {
 		op := p.previous()
 		right, err := p.term()
 
@@ -499,7 +997,7 @@ func (p *Parser) factor() (ast.Expr, error) {
 		return nil, err
 	}
 
-	for p.match(token.SLASH, token.STAR) {
+	for p.match(token.SLASH, token.STAR, token.PERCENT) {
 		op := p.previous()
 		right, err := p.unary()
 
@@ -514,16 +1012,67 @@ func (p *Parser) factor() (ast.Expr, error) {
 }
 
 func (p *Parser) unary() (ast.Expr, error) {
-	if p.match(token.BANG, token.MINUS) {
+	if p.match(token.BANG, token.MINUS, token.TILDE) {
+		operator := p.previous()
 		expr, err := p.unary()
 		if err != nil {
 			return nil, err
 		}
 
-		return ast.NewUnary(p.advance(), expr), nil
+		return ast.NewUnary(operator, expr), nil
+	}
+
+	if p.match(token.PLUS_PLUS, token.MINUS_MINUS) {
+		operator := p.previous()
+		name, err := p.consume(token.IDENTIFIER, "Expect variable name after '"+operator.Lexeme()+"'.")
+		if err != nil {
+			return nil, err
+		}
+
+		return ast.NewIncrement(name, operator, true), nil
+	}
+
+	return p.power()
+}
+
+// power parses the right-associative '**', which binds tighter than a
+// unary operator on its left: -2 ** 2 is -(2 ** 2).
+func (p *Parser) power() (ast.Expr, error) {
+	expr, err := p.postfix()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(token.STAR_STAR) {
+		op := p.previous()
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		return ast.NewBinary(expr, op, right), nil
 	}
 
-	return p.call()
+	return expr, nil
+}
+
+func (p *Parser) postfix() (ast.Expr, error) {
+	expr, err := p.call()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(token.PLUS_PLUS, token.MINUS_MINUS) {
+		operator := p.previous()
+
+		if variable, ok := expr.(*ast.Variable); ok {
+			return ast.NewIncrement(variable.Name, operator, false), nil
+		}
+
+		errors.Error(operator, "Invalid increment target.")
+	}
+
+	return expr, nil
 }
 
 func (p *Parser) call() (ast.Expr, error) {
@@ -546,20 +1095,40 @@ func (p *Parser) call() (ast.Expr, error) {
 	return expr, nil
 }
 
+// finishCall parses the arguments of a call. Named arguments, written
+// `name: value`, come after the positional ones.
 func (p *Parser) finishCall(callee ast.Expr) (ast.Expr, error) {
 	args := []ast.Expr{}
+	named := []ast.NamedArgument{}
 
 	if !p.check(token.RIGHT_PAREN) {
 		for ok := true; ok; ok = p.match(token.COMMA) {
+			if len(args)+len(named) >= 255 {
+				errors.Error(p.peek(), "Can't have more than 255 arguments.")
+			}
+
+			if p.checkNamedArgument() {
+				name := p.advance()
+				p.advance()
+
+				value, err := p.expression()
+				if err != nil {
+					return nil, err
+				}
+
+				named = append(named, ast.NamedArgument{Name: name, Value: value})
+				continue
+			}
+
+			if len(named) > 0 {
+				errors.Error(p.peek(), "Positional arguments can't follow named arguments.")
+			}
+
 			expr, err := p.expression()
 			if err != nil {
 				return nil, err
 			}
 
-			if len(args) >= 255 {
-				errors.Error(p.peek(), "Can't have more than 255 arguments.")
-			}
-
 			args = append(args, expr)
 		}
 	}
@@ -569,7 +1138,16 @@ func (p *Parser) finishCall(callee ast.Expr) (ast.Expr, error) {
 		return nil, err
 	}
 
-	return ast.NewCall(callee, paren, args), nil
+	return ast.NewCall(callee, paren, args, named), nil
+}
+
+// checkNamedArgument reports whether the next argument starts with `name:`.
+func (p Parser) checkNamedArgument() bool {
+	if !p.check(token.IDENTIFIER) || p.current+1 >= len(p.tokens) {
+		return false
+	}
+
+	return p.tokens[p.current+1].Type() == token.COLON
 }
 
 func (p *Parser) primary() (ast.Expr, error) {
@@ -581,6 +1159,10 @@ func (p *Parser) primary() (ast.Expr, error) {
 		return ast.NewLiteral(false), nil
 	}
 
+	if p.match(token.NIL) {
+		return ast.NewLiteral(nil), nil
+	}
+
 	if p.match(token.STRING, token.NUMBER) {
 		return ast.NewLiteral(p.previous().Literal()), nil
 	}
@@ -589,6 +1171,10 @@ func (p *Parser) primary() (ast.Expr, error) {
 		return ast.NewVariable(p.previous()), nil
 	}
 
+	if p.match(token.INTERPOLATION) {
+		return p.interpolation()
+	}
+
 	if p.match(token.LEFT_PAREN) {
 		expr, err := p.expression()
 
@@ -604,6 +1190,52 @@ func (p *Parser) primary() (ast.Expr, error) {
 	return nil, p.error(p.peek(), "Expect expression.")
 }
 
+// interpolation parses the segments of an interpolated string, starting
+// after its first INTERPOLATION token.
+func (p *Parser) interpolation() (ast.Expr, error) {
+	parts := []ast.Expr{}
+
+	for {
+		if segment := p.previous().Literal().(string); segment != "" {
+			parts = append(parts, ast.NewLiteral(segment))
+		}
+
+		// Segments that follow an interpolated expression start with its
+		// closing brace, which a string literal never does.
+		if next := p.peek(); (next.Type() == token.INTERPOLATION || next.Type() == token.STRING) && strings.HasPrefix(next.Lexeme(), "}") {
+			return nil, p.error(next, "Expect expression.")
+		}
+
+		expr, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+
+		parts = append(parts, expr)
+
+		if p.match(token.INTERPOLATION) {
+			continue
+		}
+
+		end, err := p.consume(token.STRING, "Expect '}' after interpolated expression.")
+		if err != nil {
+			return nil, err
+		}
+
+		if segment := end.Literal().(string); segment != "" {
+			parts = append(parts, ast.NewLiteral(segment))
+		}
+
+		return ast.NewInterpolation(parts), nil
+	}
+}
+
+// docComment returns the doc comments written before the keyword that
+// starts the declaration being parsed.
+func (p *Parser) docComment() string {
+	return p.docs[p.current-1]
+}
+
 func (p *Parser) consume(tokenType token.TokenType, message string) (token.Token, error) {
 	if p.check(tokenType) {
 		return p.advance(), nil
@@ -623,7 +1255,7 @@ func (p *Parser) synchronize() {
 		}
 
 		switch p.previous().Type() {
-		case token.CLASS, token.FUN, token.VAR, token.FOR, token.IF, token.WHILE, token.PRINT, token.RETURN:
+		case token.CLASS, token.CONST, token.FUN, token.VAR, token.FOR, token.IF, token.WHILE, token.PRINT, token.RETURN, token.SPAWN, token.SELECT, token.YIELD:
 			return
 		}
 
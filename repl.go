@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"glox/errors"
+	"glox/scanner"
+	"glox/token"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/peterh/liner"
+)
+
+const (
+	prompt             = "> "
+	continuationPrompt = "... "
+	historyFile        = ".glox_history"
+)
+
+func runPrompt() error {
+	line := liner.NewLiner()
+	defer line.Close()
+
+	line.SetCtrlCAborts(true)
+	line.SetTabCompletionStyle(liner.TabPrints)
+	line.SetWordCompleter(completeGlobals)
+
+	history := historyPath()
+	if f, err := os.Open(history); err == nil {
+		line.ReadHistory(f)
+		f.Close()
+	}
+
+	defer func() {
+		if f, err := os.Create(history); err == nil {
+			line.WriteHistory(f)
+			f.Close()
+		}
+	}()
+
+	interp.SetEcho(true)
+
+	for {
+		source, err := readInput(line)
+
+		if err == liner.ErrPromptAborted {
+			continue
+		}
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if strings.TrimSpace(source) == "" {
+			continue
+		}
+
+		if strings.HasPrefix(source, ":") {
+			runCommand(source)
+		} else {
+			runRecorded(source)
+		}
+
+		errors.Reset()
+
+		if _, ok := interp.Exited(); ok {
+			return nil
+		}
+	}
+
+	fmt.Print("\n")
+	return nil
+}
+
+// readInput reads lines until they form a complete chunk of source code,
+// switching to the continuation prompt while braces, parentheses or a
+// string are left open.
+func readInput(line *liner.State) (string, error) {
+	source := ""
+	p := prompt
+
+	for {
+		l, err := line.Prompt(p)
+		if err != nil {
+			return "", err
+		}
+
+		if strings.TrimSpace(l) != "" {
+			line.AppendHistory(l)
+		}
+
+		source += l
+
+		if strings.HasPrefix(source, ":") || !isIncomplete(source) {
+			return source, nil
+		}
+
+		source += "\n"
+		p = continuationPrompt
+	}
+}
+
+func isIncomplete(source string) bool {
+	prev := errors.SetOutput(io.Discard)
+	hadError := errors.HadError()
+
+	defer func() {
+		errors.SetOutput(prev)
+		errors.SetHadError(hadError)
+	}()
+
+	s := scanner.NewScanner(source)
+	tokens := s.ScanTokens()
+
+	if s.Unterminated() {
+		return true
+	}
+
+	depth := 0
+
+	for _, t := range tokens {
+		switch t.Type() {
+		case token.LEFT_PAREN, token.LEFT_BRACE:
+			depth++
+		case token.RIGHT_PAREN, token.RIGHT_BRACE:
+			depth--
+		}
+	}
+
+	return depth > 0
+}
+
+func completeGlobals(line string, pos int) (string, []string, string) {
+	runes := []rune(line)
+
+	start := pos
+	for start > 0 && isIdentifierRune(runes[start-1]) {
+		start--
+	}
+
+	prefix := string(runes[start:pos])
+	completions := []string{}
+
+	if prefix == "" {
+		return string(runes[:pos]), completions, string(runes[pos:])
+	}
+
+	for _, name := range interp.Globals() {
+		if strings.HasPrefix(name, prefix) {
+			completions = append(completions, name)
+		}
+	}
+
+	return string(runes[:start]), completions, string(runes[pos:])
+}
+
+func isIdentifierRune(c rune) bool {
+	return c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c)
+}
+
+func historyPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return historyFile
+	}
+
+	return filepath.Join(home, historyFile)
+}
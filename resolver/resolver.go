@@ -17,12 +17,21 @@ const (
 type Resolver struct {
 	interp *interpreter.Interpreter
 	scopes *Stack[map[string]bool]
+    // constants holds the constants declared in each scope, and
+    // globalConstants those declared at the top level.
+    constants *Stack[map[string]bool]
+    globalConstants map[string]bool
     currentFun FunctionType
+    // yields tells whether the current function contains a yield, and
+    // valueReturns lists its returns with a value, which generators forbid.
+    yields bool
+    valueReturns []token.Token
 }
 
 func NewResolver(i *interpreter.Interpreter) *Resolver {
 	s := Stack[map[string]bool]{}
-	return &Resolver{interp: i, scopes: s.New(), currentFun: NONE}
+	c := Stack[map[string]bool]{}
+	return &Resolver{interp: i, scopes: s.New(), constants: c.New(), globalConstants: map[string]bool{}, currentFun: NONE}
 }
 
 func (r *Resolver) VisitBlockStmt(s *ast.Block) error {
@@ -35,10 +44,12 @@ func (r *Resolver) VisitBlockStmt(s *ast.Block) error {
 
 func (r *Resolver) beginScope() {
 	r.scopes.Push(map[string]bool{})
+	r.constants.Push(map[string]bool{})
 }
 
 func (r *Resolver) endScope() {
 	r.scopes.Pop()
+	r.constants.Pop()
 }
 
 func (r *Resolver) Resolve(e interface{}) {
@@ -61,18 +72,33 @@ func (r *Resolver) Resolve(e interface{}) {
 
 func (r *Resolver) resolveFunction(f *ast.Function, t FunctionType) error {
     enclosingFun := r.currentFun
+    enclosingYields, enclosingReturns := r.yields, r.valueReturns
     r.currentFun = t
+    r.yields, r.valueReturns = false, nil
 
 	r.beginScope()
 	for _, p := range f.Params {
-		r.declare(p)
-		r.define(p)
+		if p.Default != nil {
+			r.Resolve(p.Default)
+		}
+
+		r.declare(p.Name)
+		r.define(p.Name)
 	}
 
 	r.Resolve(f.Body)
 	r.endScope()
 
+    if r.yields {
+        r.interp.MarkGenerator(f)
+
+        for _, keyword := range r.valueReturns {
+            errors.Error(keyword, "Can't return a value from a generator.")
+        }
+    }
+
     r.currentFun = enclosingFun
+    r.yields, r.valueReturns = enclosingYields, enclosingReturns
 
 	return nil
 }
@@ -85,6 +111,19 @@ func (r *Resolver) VisitVarStmt(s *ast.Var) error {
 	}
 
 	r.define(s.Name)
+	r.markConstant(s.Name, s.Constant)
+
+	return nil
+}
+
+func (r *Resolver) VisitForInStmt(s *ast.ForIn) error {
+	r.Resolve(s.Iterable)
+
+	r.beginScope()
+	r.declare(s.Name)
+	r.define(s.Name)
+	r.Resolve(s.Body)
+	r.endScope()
 
 	return nil
 }
@@ -92,6 +131,7 @@ func (r *Resolver) VisitVarStmt(s *ast.Var) error {
 func (r *Resolver) VisitFunctionStmt(s *ast.Function) error {
 	r.declare(s.Name)
 	r.define(s.Name)
+	r.markConstant(s.Name, false)
 
 	r.resolveFunction(s, FUNCTION)
 
@@ -112,6 +152,7 @@ func (r *Resolver) VisitVariableExpr(e *ast.Variable) (interface{}, error) {
 func (r *Resolver) VisitAssignExpr(e *ast.Assign) (interface{}, error) {
 	r.Resolve(e.Value)
 	r.resolveLocal(e, e.Name)
+	r.checkAssignable(e.Name)
 
 	return nil, nil
 }
@@ -145,11 +186,63 @@ func (r *Resolver) VisitReturnStmt(s *ast.Return) error {
 
 	if s.Value != nil {
 		r.Resolve(s.Value)
+		r.valueReturns = append(r.valueReturns, s.Keyword)
+	}
+
+	if call, ok := s.Value.(*ast.Call); ok && r.currentFun != NONE {
+		r.interp.MarkTailCall(call)
 	}
 
 	return nil
 }
 
+func (r *Resolver) VisitSelectStmt(s *ast.Select) error {
+	for _, c := range s.Cases {
+		r.Resolve(c.Channel)
+
+		if c.Value != nil {
+			r.Resolve(c.Value)
+		}
+
+		if c.Name == nil {
+			r.Resolve(c.Body)
+			continue
+		}
+
+		r.beginScope()
+		r.declare(*c.Name)
+		r.define(*c.Name)
+		r.Resolve(c.Body)
+		r.endScope()
+	}
+
+	if s.Default != nil {
+		r.Resolve(s.Default)
+	}
+
+	return nil
+}
+
+func (r *Resolver) VisitSpawnStmt(s *ast.Spawn) error {
+	r.Resolve(s.Call)
+
+	return nil
+}
+
+func (r *Resolver) VisitYieldStmt(s *ast.Yield) error {
+    if r.currentFun == NONE {
+        errors.Error(s.Keyword, "Can't yield from top-level code.")
+    }
+
+    if s.Value != nil {
+        r.Resolve(s.Value)
+    }
+
+    r.yields = true
+
+    return nil
+}
+
 func (r *Resolver) VisitWhileStmt(s *ast.While) error {
 	r.Resolve(s.Condition)
 	r.Resolve(s.Body)
@@ -171,15 +264,49 @@ func (r *Resolver) VisitCallExpr(e *ast.Call) (interface{}, error) {
 		r.Resolve(arg)
 	}
 
+	for _, arg := range e.NamedArguments {
+		r.Resolve(arg.Value)
+	}
+
 	return nil, nil
 }
 
+func (r *Resolver) VisitCoalesceExpr(e *ast.Coalesce) (interface{}, error) {
+    r.Resolve(e.Left)
+    r.Resolve(e.Right)
+
+    return nil, nil
+}
+
+func (r *Resolver) VisitConditionalExpr(e *ast.Conditional) (interface{}, error) {
+    r.Resolve(e.Condition)
+    r.Resolve(e.ThenBranch)
+    r.Resolve(e.ElseBranch)
+
+    return nil, nil
+}
+
 func (r *Resolver) VisitGroupingExpr(e *ast.Grouping) (interface{}, error) {
     r.Resolve(e.Expression)
 
     return nil, nil
 }
 
+func (r *Resolver) VisitIncrementExpr(e *ast.Increment) (interface{}, error) {
+    r.resolveLocal(e, e.Name)
+    r.checkAssignable(e.Name)
+
+    return nil, nil
+}
+
+func (r *Resolver) VisitInterpolationExpr(e *ast.Interpolation) (interface{}, error) {
+    for _, part := range e.Parts {
+        r.Resolve(part)
+    }
+
+    return nil, nil
+}
+
 func (r *Resolver) VisitLiteralExpr(e *ast.Literal) (interface{}, error) {
     return nil, nil
 }
@@ -203,10 +330,39 @@ func (r *Resolver) resolveLocal(e ast.Expr, name token.Token) {
 
 		if _, ok := scope[name.Lexeme()]; ok {
 			r.interp.Resolve(e, r.scopes.Len()-1-i)
+			return
 		}
 	}
 }
 
+// markConstant records whether the variable just declared is a constant,
+// which also clears the mark of a global redeclared as a variable.
+func (r *Resolver) markConstant(name token.Token, constant bool) {
+	if r.scopes.IsEmpty() {
+		r.globalConstants[name.Lexeme()] = constant
+		return
+	}
+
+	(*r.constants.Peek())[name.Lexeme()] = constant
+}
+
+// checkAssignable reports assignments to a constant. Globals declared after
+// the assignment is resolved are only caught at run time.
+func (r *Resolver) checkAssignable(name token.Token) {
+	constant := r.globalConstants[name.Lexeme()]
+
+	for i := r.scopes.Len() - 1; i >= 0; i-- {
+		if _, ok := (*r.scopes.Get(i))[name.Lexeme()]; ok {
+			constant = (*r.constants.Get(i))[name.Lexeme()]
+			break
+		}
+	}
+
+	if constant {
+		errors.Error(name, "Can't assign to constant '"+name.Lexeme()+"'.")
+	}
+}
+
 func (r *Resolver) declare(name token.Token) {
 	if r.scopes.IsEmpty() {
 		return
@@ -4,7 +4,10 @@ import "glox/token"
 
 var keywords = map[string]token.TokenType{
     "and": token.AND,
+    "case": token.CASE,
     "class": token.CLASS,
+    "const": token.CONST,
+    "default": token.DEFAULT,
     "else": token.ELSE,
     "false": token.FALSE,
     "for": token.FOR,
@@ -14,9 +17,12 @@ var keywords = map[string]token.TokenType{
     "or": token.OR,
     "print": token.PRINT,
     "return": token.RETURN,
+    "select": token.SELECT,
+    "spawn": token.SPAWN,
     "super": token.SUPER,
     "this": token.THIS,
     "true": token.TRUE,
     "var": token.VAR,
     "while": token.WHILE,
+    "yield": token.YIELD,
 }
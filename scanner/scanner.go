@@ -1,34 +1,67 @@
 package scanner
 
 import (
+	"fmt"
 	"glox/errors"
 	"glox/token"
 	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
 )
 
 type Scanner struct {
-    source string
+    source []rune
     tokens []token.Token
     start int
     current int
     line int
+    lineStart int
+    startLine int
+    startColumn int
+    trivia bool
+    unterminated bool
+    // interpolations holds, for each string interpolation being scanned,
+    // the number of braces opened inside of its ${...}.
+    interpolations []int
 }
 
 func NewScanner(source string) Scanner {
-    return Scanner{source: source, tokens: []token.Token{}, start: 0, current: 0, line: 1}
+    return Scanner{source: []rune(source), tokens: []token.Token{}, start: 0, current: 0, line: 1}
+}
+
+// IncludeTrivia makes the scanner emit COMMENT and WHITESPACE tokens, so
+// that the lexemes of the scanned tokens add up to the whole source.
+func (s *Scanner) IncludeTrivia() {
+    s.trivia = true
 }
 
 func (s *Scanner) ScanTokens() []token.Token {
+    s.shebang()
+
     for !s.isAtEnd() {
         s.start = s.current
+        s.startLine = s.line
+        s.startColumn = s.column()
         s.scanToken()
     }
 
-    s.tokens = append(s.tokens, token.NewToken(token.EOF, "", nil, s.line))
+    if len(s.interpolations) > 0 {
+        s.unterminated = true
+        errors.ErrorAt(s.line, "Unterminated string interpolation.")
+    }
+
+    s.tokens = append(s.tokens, token.NewToken(token.EOF, "", nil, s.line, s.column()))
 
     return s.tokens
 }
 
+// Unterminated reports whether the source ended in the middle of a string
+// or of a comment.
+func (s Scanner) Unterminated() bool {
+    return s.unterminated
+}
+
 func (s Scanner) isAtEnd() bool {
     return s.current >= len(s.source)
 }
@@ -42,21 +75,83 @@ func (s *Scanner) scanToken() {
     case ')':
         s.addToken(token.RIGHT_PAREN)
     case '{':
+        if n := len(s.interpolations); n > 0 {
+            s.interpolations[n-1]++
+        }
+
         s.addToken(token.LEFT_BRACE)
     case '}':
+        if n := len(s.interpolations); n > 0 {
+            if s.interpolations[n-1] == 0 {
+                s.interpolations = s.interpolations[:n-1]
+                s.string()
+                return
+            }
+
+            s.interpolations[n-1]--
+        }
+
         s.addToken(token.RIGHT_BRACE)
     case ',':
         s.addToken(token.COMMA)
     case '.':
-        s.addToken(token.DOT)
+        if s.peek() == '.' && s.peekNext() == '.' {
+            s.advance()
+            s.advance()
+            s.addToken(token.ELLIPSIS)
+        } else {
+            s.addToken(token.DOT)
+        }
     case '-':
-        s.addToken(token.MINUS)
+        t := token.MINUS
+        if s.increment('-') {
+            s.advance()
+            t = token.MINUS_MINUS
+        } else if s.match('=') {
+            t = token.MINUS_EQUAL
+        }
+        s.addToken(t)
     case '+':
-        s.addToken(token.PLUS)
+        t := token.PLUS
+        if s.increment('+') {
+            s.advance()
+            t = token.PLUS_PLUS
+        } else if s.match('=') {
+            t = token.PLUS_EQUAL
+        }
+        s.addToken(t)
     case ';':
         s.addToken(token.SEMICOLON)
     case '*':
-        s.addToken(token.STAR)
+        t := token.STAR
+        if s.match('*') {
+            t = token.STAR_STAR
+        } else if s.match('=') {
+            t = token.STAR_EQUAL
+        }
+        s.addToken(t)
+    case '%':
+        t := token.PERCENT
+        if s.match('=') {
+            t = token.PERCENT_EQUAL
+        }
+        s.addToken(t)
+    case '&':
+        s.addToken(token.AMPERSAND)
+    case '|':
+        s.addToken(token.PIPE)
+    case '^':
+        s.addToken(token.CARET)
+    case '~':
+        s.addToken(token.TILDE)
+    case '?':
+        t := token.QUESTION
+        if s.match('?') {
+            t = token.QUESTION_QUESTION
+        }
+        s.addToken(t)
+    case ':':
+        s.addToken(token.COLON)
     case '!':
         t := token.BANG
         if s.match('=') {
@@ -73,33 +168,39 @@ func (s *Scanner) scanToken() {
         t := token.LESS
         if s.match('=') {
             t = token.LESS_EQUAL
+        } else if s.match('<') {
+            t = token.LESS_LESS
         }
         s.addToken(t);
     case '>':
         t := token.GREATER
         if s.match('=') {
             t = token.GREATER_EQUAL
+        } else if s.match('>') {
+            t = token.GREATER_GREATER
         }
         s.addToken(t);
 
     case '/':
         if s.match('/') {
-            for s.peek() != '\n' && !s.isAtEnd() {
-                s.advance()
-            }
+            s.lineComment()
+        } else if s.match('*') {
+            s.blockComment()
+        } else if s.match('=') {
+            s.addToken(token.SLASH_EQUAL)
         } else {
             s.addToken(token.SLASH)
         }
 
-    case ' ', '\r', '\t':
-        break
-
-    case '\n':
-        s.line++
+    case ' ', '\r', '\t', '\n':
+        s.whitespace(c)
 
     case '"':
         s.string()
 
+    case '`':
+        s.rawString()
+
     default:
         if isDigit(c) {
             s.number()
@@ -111,7 +212,141 @@ func (s *Scanner) scanToken() {
     }
 }
 
-func (s *Scanner) advance() byte {
+// shebang skips a "#!" line at the very beginning of the source, so that
+// scripts can be run directly.
+func (s *Scanner) shebang() {
+    if len(s.source) < 2 || s.source[0] != '#' || s.source[1] != '!' {
+        return
+    }
+
+    s.startLine = s.line
+    s.startColumn = s.column()
+
+    for s.peek() != '\n' && !s.isAtEnd() {
+        s.advance()
+    }
+
+    s.addTrivia(token.COMMENT)
+}
+
+// lineComment scans a comment up to the end of the line. Comments starting
+// with exactly three slashes are documentation comments, which the parser
+// attaches to the declaration that follows them.
+func (s *Scanner) lineComment() {
+    doc := s.peek() == '/' && s.peekNext() != '/'
+
+    for s.peek() != '\n' && !s.isAtEnd() {
+        s.advance()
+    }
+
+    if !doc {
+        s.addTrivia(token.COMMENT)
+        return
+    }
+
+    text := string(s.source[s.start+3 : s.current])
+    s.addTokenWithLiteral(token.DOC_COMMENT, strings.TrimPrefix(text, " "))
+}
+
+// blockComment scans a /* ... */ comment, which may contain nested ones.
+func (s *Scanner) blockComment() {
+    depth := 1
+
+    for depth > 0 && !s.isAtEnd() {
+        c := s.advance()
+
+        switch {
+        case c == '\n':
+            s.newLine()
+        case c == '/' && s.peek() == '*':
+            s.advance()
+            depth++
+        case c == '*' && s.peek() == '/':
+            s.advance()
+            depth--
+        }
+    }
+
+    if depth > 0 {
+        s.unterminated = true
+        errors.ErrorAt(s.line, "Unterminated comment.")
+        return
+    }
+
+    s.addTrivia(token.COMMENT)
+}
+
+func (s *Scanner) whitespace(c rune) {
+    for {
+        if c == '\n' {
+            s.newLine()
+        }
+
+        if !isWhitespace(s.peek()) {
+            break
+        }
+
+        c = s.advance()
+    }
+
+    s.addTrivia(token.WHITESPACE)
+}
+
+func (s *Scanner) newLine() {
+    s.line++
+    s.lineStart = s.current
+}
+
+func (s Scanner) column() int {
+    return s.current - s.lineStart + 1
+}
+
+func (s *Scanner) addTrivia(tokenType token.TokenType) {
+    if s.trivia {
+        s.addToken(tokenType)
+    }
+}
+
+// increment reports whether c, following the same character, makes an
+// increment operator. That is only the case after a variable, as in `x--`,
+// or before one, as in `--x`, so that `1--1` still means `1 - -1`.
+func (s *Scanner) increment(c rune) bool {
+    if s.peek() != c {
+        return false
+    }
+
+    next := s.current + 1
+    for next < len(s.source) && (s.source[next] == ' ' || s.source[next] == '\t') {
+        next++
+    }
+
+    operandAfter := next < len(s.source) && (isAlphaNumeric(s.source[next]) || s.source[next] == '(' || s.source[next] == '"' || s.source[next] == '`')
+
+    switch s.previousToken() {
+    case token.IDENTIFIER:
+        return !operandAfter
+    case token.NUMBER, token.STRING, token.RIGHT_PAREN, token.TRUE, token.FALSE, token.NIL:
+        return false
+    }
+
+    return next < len(s.source) && isAlpha(s.source[next])
+}
+
+// previousToken returns the type of the last token scanned, ignoring
+// trivia.
+func (s *Scanner) previousToken() token.TokenType {
+    for i := len(s.tokens) - 1; i >= 0; i-- {
+        switch t := s.tokens[i].Type(); t {
+        case token.COMMENT, token.DOC_COMMENT, token.WHITESPACE:
+        default:
+            return t
+        }
+    }
+
+    return token.EOF
+}
+
+func (s *Scanner) advance() rune {
     c := s.source[s.current]
     s.current++
 
@@ -123,11 +358,11 @@ func (s *Scanner) addToken(tokenType token.TokenType) {
 }
 
 func (s *Scanner) addTokenWithLiteral(tokenType token.TokenType, literal interface{}) {
-    text := s.source[s.start:s.current]
-    s.tokens = append(s.tokens, token.NewToken(tokenType, text, literal, s.line))
+    text := string(s.source[s.start:s.current])
+    s.tokens = append(s.tokens, token.NewToken(tokenType, text, literal, s.startLine, s.startColumn))
 }
 
-func (s *Scanner) match(expected byte) bool {
+func (s *Scanner) match(expected rune) bool {
     if s.isAtEnd() {
         return false
     }
@@ -141,7 +376,7 @@ func (s *Scanner) match(expected byte) bool {
     return true
 }
 
-func (s Scanner) peek() byte {
+func (s Scanner) peek() rune {
     if s.isAtEnd() {
         return 0
     }
@@ -149,7 +384,7 @@ func (s Scanner) peek() byte {
     return s.source[s.current]
 }
 
-func (s Scanner) peekNext() byte {
+func (s Scanner) peekNext() rune {
     if s.current + 1 >= len(s.source) {
         return 0
     }
@@ -157,23 +392,122 @@ func (s Scanner) peekNext() byte {
     return s.source[s.current + 1]
 }
 
+// string scans the content of a string up to its closing quote, or up to
+// the next ${ which is emitted as an INTERPOLATION token. In the latter case
+// scanning resumes here after the matching }.
 func (s *Scanner) string() {
+    var value strings.Builder
+
     for s.peek() != '"' && !s.isAtEnd() {
-        if s.peek() == '\n' {
-            s.line++
+        c := s.advance()
+
+        switch c {
+        case '$':
+            if s.match('{') {
+                s.interpolations = append(s.interpolations, 0)
+                s.addTokenWithLiteral(token.INTERPOLATION, value.String())
+                return
+            }
+        case '\n':
+            s.newLine()
+        case '\\':
+            if s.isAtEnd() {
+                continue
+            }
+
+            c = s.escape()
+        }
+
+        if c >= 0 {
+            value.WriteRune(c)
         }
+    }
 
+    if s.isAtEnd() {
+        s.unterminated = true
+        errors.ErrorAt(s.line, "Unterminated string.")
+        return
+    }
+
+    s.advance()
+
+    s.addTokenWithLiteral(token.STRING, value.String())
+}
+
+// escape decodes the escape sequence following a backslash. It returns -1
+// for a line continuation and for invalid sequences, which it reports.
+func (s *Scanner) escape() rune {
+    c := s.advance()
+
+    switch c {
+    case 'n':
+        return '\n'
+    case 't':
+        return '\t'
+    case 'r':
+        return '\r'
+    case '0':
+        return 0
+    case '"', '\\', '`', '$':
+        return c
+    case 'u':
+        return s.unicodeEscape()
+    case '\n':
+        s.newLine()
+        return -1
+    }
+
+    errors.ErrorAt(s.line, fmt.Sprintf("Invalid escape sequence '\\%c'.", c))
+
+    return -1
+}
+
+// unicodeEscape decodes the code point of a \u{XXXX} escape sequence.
+func (s *Scanner) unicodeEscape() rune {
+    if !s.match('{') {
+        errors.ErrorAt(s.line, "Expect '{' after '\\u'.")
+        return -1
+    }
+
+    start := s.current
+    for isHexDigit(s.peek()) {
         s.advance()
     }
 
+    digits := string(s.source[start:s.current])
+
+    if !s.match('}') {
+        errors.ErrorAt(s.line, "Expect '}' after unicode escape sequence.")
+        return -1
+    }
+
+    code, err := strconv.ParseUint(digits, 16, 32)
+    if err != nil || code > unicode.MaxRune || utf16.IsSurrogate(rune(code)) {
+        errors.ErrorAt(s.line, "Invalid unicode escape sequence '\\u{"+digits+"}'.")
+        return -1
+    }
+
+    return rune(code)
+}
+
+// rawString scans a string between backquotes. It may span several lines
+// and its content is taken as is, without escape sequences.
+func (s *Scanner) rawString() {
+    for s.peek() != '`' && !s.isAtEnd() {
+        if s.advance() == '\n' {
+            s.newLine()
+        }
+    }
+
     if s.isAtEnd() {
+        s.unterminated = true
         errors.ErrorAt(s.line, "Unterminated string.")
         return
     }
 
     s.advance()
-    
-    value := s.source[s.start + 1: s.current - 1]
+
+    value := string(s.source[s.start+1 : s.current-1])
 
     s.addTokenWithLiteral(token.STRING, value)
 }
@@ -191,7 +525,7 @@ func (s *Scanner) number() {
         }
     }
 
-    if f, err := strconv.ParseFloat(s.source[s.start:s.current], 64); err == nil {
+    if f, err := strconv.ParseFloat(string(s.source[s.start:s.current]), 64); err == nil {
         s.addTokenWithLiteral(token.NUMBER, f)
     }
 }
@@ -201,7 +535,7 @@ func (s *Scanner) identifier() {
         s.advance()
     }
     
-    text := s.source[s.start:s.current]
+    text := string(s.source[s.start:s.current])
     tokenType, isKeyword := keywords[text]
 
     if !isKeyword {
@@ -211,14 +545,22 @@ func (s *Scanner) identifier() {
     s.addToken(tokenType)
 }
  
-func isDigit(c byte) bool {
+func isWhitespace(c rune) bool {
+    return c == ' ' || c == '\r' || c == '\t' || c == '\n'
+}
+
+func isHexDigit(c rune) bool {
+    return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func isDigit(c rune) bool {
     return c >= '0' && c <= '9'
 }
 
-func isAlpha(c byte) bool {
-    return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c == '_')
+func isAlpha(c rune) bool {
+    return unicode.IsLetter(c) || c == '_'
 }
 
-func isAlphaNumeric(c byte) bool {
-    return isDigit(c) || isAlpha(c)
+func isAlphaNumeric(c rune) bool {
+    return isAlpha(c) || unicode.IsDigit(c)
 }
@@ -1,16 +1,20 @@
 package token
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+)
 
 type Token struct {
     tokenType TokenType
     lexeme string
     literal interface{}
     line int
+    column int
 }
 
-func NewToken(tokenType TokenType, lexeme string, literal interface{}, line int) Token{
-    return Token{tokenType: tokenType, lexeme: lexeme, literal: literal, line: line}
+func NewToken(tokenType TokenType, lexeme string, literal interface{}, line int, column int) Token{
+    return Token{tokenType: tokenType, lexeme: lexeme, literal: literal, line: line, column: column}
 }
 
 func (t Token) String() string {
@@ -32,3 +36,18 @@ func (t Token) Literal() interface{} {
 func (t Token) Line() int {
     return t.line
 }
+
+// Column is the 1-based position of the token's first character on its line.
+func (t Token) Column() int {
+    return t.column
+}
+
+func (t Token) MarshalJSON() ([]byte, error) {
+    return json.Marshal(struct {
+        Type    TokenType   `json:"type"`
+        Lexeme  string      `json:"lexeme"`
+        Literal interface{} `json:"literal,omitempty"`
+        Line    int         `json:"line"`
+        Column  int         `json:"column"`
+    }{t.tokenType, t.lexeme, t.literal, t.line, t.column})
+}
@@ -9,11 +9,19 @@ const (
     RIGHT_BRACE TokenType = "RIGHT_BRACE"
     COMMA TokenType = "COMMA"
     DOT TokenType = "DOT"
+    ELLIPSIS TokenType = "ELLIPSIS"
     MINUS TokenType = "MINUS"
     PLUS TokenType = "PLUS"
     SEMICOLON TokenType = "SEMICOLON"
     SLASH TokenType = "SLASH"
     STAR TokenType = "STAR"
+    PERCENT TokenType = "PERCENT"
+    AMPERSAND TokenType = "AMPERSAND"
+    PIPE TokenType = "PIPE"
+    CARET TokenType = "CARET"
+    TILDE TokenType = "TILDE"
+    QUESTION TokenType = "QUESTION"
+    COLON TokenType = "COLON"
 
     BANG TokenType = "BANG"
     BANG_EQUAL TokenType = "BANG_EQUAL"
@@ -23,12 +31,27 @@ const (
     GREATER_EQUAL TokenType = "GREATER_EQUAL"
     LESS TokenType = "LESS"
     LESS_EQUAL TokenType = "LESS_EQUAL"
+    QUESTION_QUESTION TokenType = "QUESTION_QUESTION"
+    STAR_STAR TokenType = "STAR_STAR"
+    LESS_LESS TokenType = "LESS_LESS"
+    GREATER_GREATER TokenType = "GREATER_GREATER"
+    PLUS_PLUS TokenType = "PLUS_PLUS"
+    MINUS_MINUS TokenType = "MINUS_MINUS"
+    PLUS_EQUAL TokenType = "PLUS_EQUAL"
+    MINUS_EQUAL TokenType = "MINUS_EQUAL"
+    STAR_EQUAL TokenType = "STAR_EQUAL"
+    SLASH_EQUAL TokenType = "SLASH_EQUAL"
+    PERCENT_EQUAL TokenType = "PERCENT_EQUAL"
 
     IDENTIFIER TokenType = "IDENTIFIER"
     STRING TokenType = "STRING"
+    INTERPOLATION TokenType = "INTERPOLATION"
     NUMBER TokenType = "NUMBER"
     AND TokenType = "AND"
+    CASE TokenType = "CASE"
     CLASS TokenType = "CLASS"
+    CONST TokenType = "CONST"
+    DEFAULT TokenType = "DEFAULT"
     ELSE TokenType = "ELSE"
     FALSE TokenType = "FALSE"
     FUN TokenType = "FUN"
@@ -38,11 +61,18 @@ const (
     OR TokenType = "OR"
     PRINT TokenType = "PRINT"
     RETURN TokenType = "RETURN"
+    SELECT TokenType = "SELECT"
+    SPAWN TokenType = "SPAWN"
     SUPER TokenType = "SUPER"
     THIS TokenType = "THIS"
     TRUE TokenType = "TRUE"
     VAR TokenType = "VAR"
     WHILE TokenType = "WHILE"
+    YIELD TokenType = "YIELD"
+
+    COMMENT TokenType = "COMMENT"
+    DOC_COMMENT TokenType = "DOC_COMMENT"
+    WHITESPACE TokenType = "WHITESPACE"
 
     EOF TokenType = "EOF"
 )
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"glox/errors"
+	"glox/scanner"
+	"os"
+)
+
+func tokensCommand(args []string) int {
+	fs := flag.NewFlagSet("tokens", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print one JSON object per token")
+	trivia := fs.Bool("trivia", false, "include comments and whitespace")
+
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: glox tokens [--json] [--trivia] file")
+		fs.PrintDefaults()
+	}
+
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 64
+	}
+
+	b, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 66
+	}
+
+	s := scanner.NewScanner(string(b))
+	if *trivia {
+		s.IncludeTrivia()
+	}
+
+	for _, t := range s.ScanTokens() {
+		if *asJSON {
+			line, err := json.Marshal(t)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 70
+			}
+
+			fmt.Println(string(line))
+			continue
+		}
+
+		literal := ""
+		switch l := t.Literal().(type) {
+		case nil:
+		case string:
+			literal = fmt.Sprintf("%q", l)
+		default:
+			literal = fmt.Sprintf("%v", l)
+		}
+
+		fmt.Printf("%4d:%-4d %-14v %-16q %v\n", t.Line(), t.Column(), t.Type(), t.Lexeme(), literal)
+	}
+
+	if errors.HadError() {
+		return 65
+	}
+
+	return 0
+}
@@ -20,8 +20,12 @@ func main() {
 	defineAst(outputDir, "Expr", []string{
 		"Assign   : Name token.Token, Value Expr",
 		"Binary   : Left Expr, Operator token.Token, Right Expr",
-        "Call     : Callee Expr, Paren token.Token, Arguments []Expr",
+        "Call     : Callee Expr, Paren token.Token, Arguments []Expr, NamedArguments []NamedArgument",
+		"Coalesce : Left Expr, Operator token.Token, Right Expr",
+		"Conditional : Condition Expr, ThenBranch Expr, ElseBranch Expr",
 		"Grouping : Expression Expr",
+		"Increment : Name token.Token, Operator token.Token, Prefix bool",
+		"Interpolation : Parts []Expr",
 		"Literal  : Value interface{}",
         "Logical  : Left Expr, Operator token.Token, Right Expr",
 		"Unary    : Operator token.Token, Right Expr",
@@ -31,12 +35,16 @@ func main() {
 	defineAst(outputDir, "Stmt", []string{
 		"Block      : Statements []Stmt",
 		"Expression : Exp Expr",
-        "Function   : Name token.Token, Params []token.Token, Body []Stmt",
+		"ForIn      : Name token.Token, Keyword token.Token, Iterable Expr, Body Stmt",
+        "Function   : Name token.Token, Params []Param, ReturnType *Type, Body []Stmt, Doc string",
         "If         : Condition Expr, ThenBranch Stmt, ElseBranch Stmt",
 		"Print      : Exp Expr",
         "Return     : Keyword token.Token, Value Expr",
-		"Var        : Name token.Token, Initializer Expr",
+		"Select     : Keyword token.Token, Cases []SelectCase, Default Stmt",
+		"Spawn      : Keyword token.Token, Call *Call",
+		"Var        : Name token.Token, Type *Type, Initializer Expr, Doc string, Constant bool",
         "While      : Condition Expr, Body Stmt",
+		"Yield      : Keyword token.Token, Value Expr",
 	}, "error")
 }
 